@@ -1,72 +1,22 @@
 package main
 
 import (
-	"fmt"
-	"image"
-	"image/color"
-	"image/gif"
-	"io"
 	"log"
-	"math"
-	"math/rand"
 	"net/http"
-	"strconv" // added 'strconv' package
-)
-
-func handler(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, "%s %s %s\n", r.Method, r.URL, r.Proto)
-	for k, v := range r.Header {
-		fmt.Fprintf(w, "Header[%q] = %q\n", k, v)
-	}
-	fmt.Fprintf(w, "Host = %q\n", r.Host)
-	fmt.Fprintf(w, "RemoteAddr = %q\n", r.RemoteAddr)
-	if err := r.ParseForm(); err != nil {
-		log.Print(err)
-	}
-	for k, v := range r.Form {
-		fmt.Fprintf(w, "Form[%q] = %q\n", k, v)
-	}
-}
-
-// implemented the 'lissajous' function from before and added the 'cycles' parameter which is a type of int
-func lissajous(out io.Writer, cycles int) {
-	const (
-		res     = 0.001
-		size    = 100
-		nframes = 64
-		delay   = 8
-	)
-
-	freq := rand.Float64() * 3.0
-	anim := gif.GIF{LoopCount: nframes}
-	phase := 0.0
 
-	palette := []color.Color{color.White, color.Black}
-
-	for i := 0; i < nframes; i++ {
-		rect := image.Rect(0, 0, 2*size+1, 2*size+1)
-		img := image.NewPaletted(rect, palette)
-		for t := 0.0; t < float64(cycles)*2*math.Pi; t += res { // we need to cast 'cycles' to a 'floor64' because of the 'math' package that expect 'float64' inputs
-			x := math.Sin(t)
-			y := math.Sin(t*freq + phase)
-			img.SetColorIndex(size+int(x*size+0.5), size+int(y*size+0.5), 1)
-		}
-		phase += 0.1
-		anim.Delay = append(anim.Delay, delay)
-		anim.Image = append(anim.Image, img)
-	}
-
-	gif.EncodeAll(out, &anim)
-}
+	"GoBookSolutions/1.12/debugecho"
+	"GoBookSolutions/1.12/lissajous"
+)
 
 func main() {
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		cyclesStr := r.FormValue("cycles")
-		cyclesInt, err := strconv.Atoi(cyclesStr) // here we used 'strconv.Atoi' function to convert the string parameter to an integer
-		if err != nil {
-			cyclesInt = 5 // default value for 'cycles'
-		}
-		lissajous(w, cyclesInt)
-	})
+	// The 'lissajous' function from before, with its 'cycles' parameter read from the
+	// request, now lives in the 'lissajous' package as an 'http.Handler', so the same
+	// code can be mounted by the combined server in 'Chapter 3/server'.
+	http.Handle("/", lissajous.Handler{})
+
+	// The request-echo handler that used to sit unused in this file now lives in the
+	// 'debugecho' package. It redacts credentials, caps the body it reads and can
+	// answer in JSON, text or HTML (see '?format=' or the Accept header).
+	http.Handle("/debug/echo", debugecho.New())
 	log.Fatal(http.ListenAndServe(":8000", nil))
 }
@@ -0,0 +1,426 @@
+// Package debugecho serves a request-echo endpoint that describes the request it
+// received: method, URL, headers, TLS and peer details, form values and uploaded
+// file metadata. It is meant to sit behind proxies and load balancers so we can see
+// what actually reaches the application. Credentials are never echoed back: the
+// values of sensitive headers such as 'Authorization' and 'Cookie' are redacted.
+
+package debugecho
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxBodyBytes is the number of body bytes read when 'Handler.MaxBodyBytes'
+// is zero. Anything beyond it is reported as truncated and not parsed.
+const DefaultMaxBodyBytes = 1 << 20
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders lists the canonical names of headers whose values are replaced
+// before they are echoed.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"X-Auth-Token":        true,
+	"X-Csrf-Token":        true,
+	"X-Xsrf-Token":        true,
+}
+
+// Handler is an 'http.Handler' that echoes the request back to the client. The
+// zero value is ready to use.
+type Handler struct {
+	// MaxBodyBytes caps how much of the request body is read. Zero means
+	// 'DefaultMaxBodyBytes'.
+	MaxBodyBytes int64
+
+	// Redact names extra headers to redact on top of the built-in list.
+	Redact []string
+}
+
+// New returns a Handler with the default limits.
+func New() *Handler { return &Handler{} }
+
+// Report is the description of a request that gets rendered as JSON, text or HTML.
+type Report struct {
+	Time             time.Time           `json:"time"`
+	Method           string              `json:"method"`
+	URL              string              `json:"url"`
+	Proto            string              `json:"proto"`
+	Host             string              `json:"host"`
+	Peer             Peer                `json:"peer"`
+	Headers          []Header            `json:"headers"`
+	ContentLength    int64               `json:"contentLength"`
+	TransferEncoding []string            `json:"transferEncoding,omitempty"`
+	TLS              *TLSInfo            `json:"tls,omitempty"`
+	Form             map[string][]string `json:"form,omitempty"`
+	Files            []File              `json:"files,omitempty"`
+	Body             Body                `json:"body"`
+	Errors           []string            `json:"errors,omitempty"`
+}
+
+// Peer holds the address of the connection the request arrived on.
+type Peer struct {
+	RemoteAddr string `json:"remoteAddr"`
+	IP         string `json:"ip,omitempty"`
+	Port       string `json:"port,omitempty"`
+	LocalAddr  string `json:"localAddr,omitempty"`
+}
+
+// Header is a single header name with all its values, in the order received.
+type Header struct {
+	Name     string   `json:"name"`
+	Values   []string `json:"values"`
+	Redacted bool     `json:"redacted,omitempty"`
+}
+
+// TLSInfo describes the TLS connection state, if the request came over TLS.
+type TLSInfo struct {
+	Version            string     `json:"version"`
+	CipherSuite        string     `json:"cipherSuite"`
+	ServerName         string     `json:"serverName,omitempty"`
+	NegotiatedProtocol string     `json:"negotiatedProtocol,omitempty"`
+	DidResume          bool       `json:"didResume"`
+	PeerCertificates   []CertInfo `json:"peerCertificates,omitempty"`
+}
+
+// CertInfo is a short summary of a client certificate.
+type CertInfo struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+}
+
+// File is the metadata of a multipart file part. The contents are never echoed.
+type File struct {
+	Field       string `json:"field"`
+	Filename    string `json:"filename"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// Body is the (possibly truncated) request body. Non-UTF-8 and multipart bodies
+// are not echoed, only their size is reported.
+type Body struct {
+	Size      int    `json:"size"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Omitted   string `json:"omitted,omitempty"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rep := h.report(r)
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	switch format(r) {
+	case "json":
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(rep)
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		htmlReport.Execute(w, rep)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writeText(w, rep)
+	}
+}
+
+// format picks the output format from the 'format' query parameter, falling back
+// to the Accept header and finally to plain text.
+func format(r *http.Request) string {
+	switch f := strings.ToLower(r.URL.Query().Get("format")); f {
+	case "json", "text", "html":
+		return f
+	}
+	accept := r.Header.Get("Accept")
+	for _, part := range strings.Split(accept, ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mt {
+		case "application/json":
+			return "json"
+		case "text/html":
+			return "html"
+		case "text/plain":
+			return "text"
+		}
+	}
+	return "text"
+}
+
+func (h *Handler) report(r *http.Request) *Report {
+	rep := &Report{
+		Time:             time.Now().UTC(),
+		Method:           r.Method,
+		URL:              r.URL.String(),
+		Proto:            r.Proto,
+		Host:             r.Host,
+		ContentLength:    r.ContentLength,
+		TransferEncoding: r.TransferEncoding,
+		Peer:             peer(r),
+		TLS:              tlsInfo(r.TLS),
+	}
+	rep.Headers = h.headers(r.Header)
+
+	limit := h.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+
+	// We read at most one byte past the limit, which is enough to tell whether the
+	// body was cut short without ever holding more than 'limit' bytes of it.
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(r.Body, limit+1))
+		if err != nil {
+			rep.Errors = append(rep.Errors, fmt.Sprintf("reading body: %v", err))
+		}
+	}
+	if int64(len(body)) > limit {
+		body = body[:limit]
+		rep.Body.Truncated = true
+	}
+	rep.Body.Size = len(body)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case rep.Body.Truncated:
+		rep.Body.Omitted = "form not parsed: body exceeds limit"
+		rep.Form = r.URL.Query()
+	case mediaType == "multipart/form-data":
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err := r.ParseMultipartForm(limit); err != nil {
+			rep.Errors = append(rep.Errors, fmt.Sprintf("parsing multipart form: %v", err))
+		}
+		if r.MultipartForm != nil {
+			defer r.MultipartForm.RemoveAll()
+			rep.Files = files(r)
+		}
+		rep.Form = r.Form
+		rep.Body.Omitted = "multipart body; see form and files"
+	default:
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err := r.ParseForm(); err != nil {
+			rep.Errors = append(rep.Errors, fmt.Sprintf("parsing form: %v", err))
+		}
+		rep.Form = r.Form
+		if utf8.Valid(body) {
+			rep.Body.Text = string(body)
+		} else {
+			rep.Body.Omitted = "binary body"
+		}
+	}
+	if len(rep.Form) == 0 {
+		rep.Form = nil
+	}
+	return rep
+}
+
+// headers copies the request headers in name order, replacing the values of the
+// sensitive ones. For 'Authorization' style headers the scheme is kept and for
+// cookies the names are kept, since those are what proxies tend to get wrong.
+func (h *Handler) headers(hdr http.Header) []Header {
+	extra := make(map[string]bool)
+	for _, name := range h.Redact {
+		extra[textproto.CanonicalMIMEHeaderKey(name)] = true
+	}
+
+	names := make([]string, 0, len(hdr))
+	for name := range hdr {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]Header, 0, len(names))
+	for _, name := range names {
+		canon := textproto.CanonicalMIMEHeaderKey(name)
+		values := append([]string(nil), hdr[name]...)
+		hide := sensitiveHeaders[canon] || extra[canon]
+		if hide {
+			for i, v := range values {
+				values[i] = redact(canon, v)
+			}
+		}
+		out = append(out, Header{Name: name, Values: values, Redacted: hide})
+	}
+	return out
+}
+
+func redact(name, value string) string {
+	switch name {
+	case "Authorization", "Proxy-Authorization":
+		if scheme, _, ok := strings.Cut(strings.TrimSpace(value), " "); ok {
+			return scheme + " " + redacted
+		}
+	case "Cookie":
+		var parts []string
+		for _, c := range strings.Split(value, ";") {
+			cname, _, _ := strings.Cut(strings.TrimSpace(c), "=")
+			if cname != "" {
+				parts = append(parts, cname+"="+redacted)
+			}
+		}
+		return strings.Join(parts, "; ")
+	case "Set-Cookie":
+		cname, _, _ := strings.Cut(value, "=")
+		return strings.TrimSpace(cname) + "=" + redacted
+	}
+	return redacted
+}
+
+func peer(r *http.Request) Peer {
+	p := Peer{RemoteAddr: r.RemoteAddr}
+	if host, port, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		p.IP, p.Port = host, port
+	}
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		p.LocalAddr = addr.String()
+	}
+	return p
+}
+
+func tlsInfo(cs *tls.ConnectionState) *TLSInfo {
+	if cs == nil {
+		return nil
+	}
+	info := &TLSInfo{
+		Version:            tls.VersionName(cs.Version),
+		CipherSuite:        tls.CipherSuiteName(cs.CipherSuite),
+		ServerName:         cs.ServerName,
+		NegotiatedProtocol: cs.NegotiatedProtocol,
+		DidResume:          cs.DidResume,
+	}
+	for _, cert := range cs.PeerCertificates {
+		info.PeerCertificates = append(info.PeerCertificates, CertInfo{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+		})
+	}
+	return info
+}
+
+// files lists the uploaded file parts sorted by field name and then file name.
+func files(r *http.Request) []File {
+	var out []File
+	for field, headers := range r.MultipartForm.File {
+		for _, fh := range headers {
+			out = append(out, File{
+				Field:       field,
+				Filename:    fh.Filename,
+				Size:        fh.Size,
+				ContentType: fh.Header.Get("Content-Type"),
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Field != out[j].Field {
+			return out[i].Field < out[j].Field
+		}
+		return out[i].Filename < out[j].Filename
+	})
+	return out
+}
+
+// writeText keeps the layout of the original 1.12 handler, one fact per line, with
+// the map based sections sorted so that two dumps can be diffed.
+func writeText(w io.Writer, rep *Report) {
+	fmt.Fprintf(w, "%s %s %s\n", rep.Method, rep.URL, rep.Proto)
+	for _, h := range rep.Headers {
+		fmt.Fprintf(w, "Header[%q] = %q\n", h.Name, h.Values)
+	}
+	fmt.Fprintf(w, "Host = %q\n", rep.Host)
+	fmt.Fprintf(w, "RemoteAddr = %q\n", rep.Peer.RemoteAddr)
+	if rep.Peer.LocalAddr != "" {
+		fmt.Fprintf(w, "LocalAddr = %q\n", rep.Peer.LocalAddr)
+	}
+	fmt.Fprintf(w, "ContentLength = %d\n", rep.ContentLength)
+	if len(rep.TransferEncoding) > 0 {
+		fmt.Fprintf(w, "TransferEncoding = %q\n", rep.TransferEncoding)
+	}
+	if t := rep.TLS; t != nil {
+		fmt.Fprintf(w, "TLS = %s %s\n", t.Version, t.CipherSuite)
+		fmt.Fprintf(w, "TLS.ServerName = %q\n", t.ServerName)
+		fmt.Fprintf(w, "TLS.NegotiatedProtocol = %q\n", t.NegotiatedProtocol)
+		for i, c := range t.PeerCertificates {
+			fmt.Fprintf(w, "TLS.PeerCertificate[%d] = %q issued by %q, valid until %s\n",
+				i, c.Subject, c.Issuer, c.NotAfter.Format(time.RFC3339))
+		}
+	}
+	keys := make([]string, 0, len(rep.Form))
+	for k := range rep.Form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "Form[%q] = %q\n", k, rep.Form[k])
+	}
+	for _, f := range rep.Files {
+		fmt.Fprintf(w, "File[%q] = %q (%d bytes, %s)\n", f.Field, f.Filename, f.Size, f.ContentType)
+	}
+	fmt.Fprintf(w, "Body = %d bytes", rep.Body.Size)
+	if rep.Body.Truncated {
+		fmt.Fprint(w, " (truncated)")
+	}
+	fmt.Fprintln(w)
+	if rep.Body.Omitted != "" {
+		fmt.Fprintf(w, "Body omitted: %s\n", rep.Body.Omitted)
+	} else if rep.Body.Text != "" {
+		fmt.Fprintf(w, "\n%s\n", rep.Body.Text)
+	}
+	for _, e := range rep.Errors {
+		fmt.Fprintf(w, "Error: %s\n", e)
+	}
+}
+
+var htmlReport = template.Must(template.New("echo").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Method}} {{.URL}}</title>
+<style>body{font-family:monospace}td{vertical-align:top;padding:0 1em 0 0}</style></head>
+<body>
+<h1>{{.Method}} {{.URL}} {{.Proto}}</h1>
+<table>
+<tr><td>Host</td><td>{{.Host}}</td></tr>
+<tr><td>RemoteAddr</td><td>{{.Peer.RemoteAddr}}</td></tr>
+<tr><td>LocalAddr</td><td>{{.Peer.LocalAddr}}</td></tr>
+<tr><td>ContentLength</td><td>{{.ContentLength}}</td></tr>
+{{with .TLS}}<tr><td>TLS</td><td>{{.Version}} {{.CipherSuite}} {{.ServerName}} {{.NegotiatedProtocol}}</td></tr>
+{{range .PeerCertificates}}<tr><td>Client cert</td><td>{{.Subject}} (issuer {{.Issuer}}, until {{.NotAfter}})</td></tr>
+{{end}}{{end}}</table>
+<h2>Headers</h2>
+<table>{{range .Headers}}<tr><td>{{.Name}}</td><td>{{range .Values}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>
+{{if .Form}}<h2>Form</h2>
+<table>{{range $k, $v := .Form}}<tr><td>{{$k}}</td><td>{{range $v}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Files}}<h2>Files</h2>
+<table>{{range .Files}}<tr><td>{{.Field}}</td><td>{{.Filename}}</td><td>{{.Size}} bytes</td><td>{{.ContentType}}</td></tr>
+{{end}}</table>{{end}}
+<h2>Body ({{.Body.Size}} bytes{{if .Body.Truncated}}, truncated{{end}})</h2>
+{{if .Body.Omitted}}<p>{{.Body.Omitted}}</p>{{else}}<pre>{{.Body.Text}}</pre>{{end}}
+{{range .Errors}}<p>Error: {{.}}</p>
+{{end}}</body></html>
+`))
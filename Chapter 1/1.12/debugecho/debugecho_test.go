@@ -0,0 +1,125 @@
+// These tests drive the echo handler through 'httptest' and check the parts we rely
+// on when debugging proxies: credentials are redacted, the body is capped and
+// multipart uploads are reported by their metadata only. Run them with 'go test ./...'.
+
+package debugecho
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveJSON(t *testing.T, h http.Handler, req *http.Request) Report {
+	t.Helper()
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var rep Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("decoding response: %v\n%s", err, rec.Body.String())
+	}
+	return rep
+}
+
+func header(rep Report, name string) []string {
+	for _, h := range rep.Headers {
+		if h.Name == name {
+			return h.Values
+		}
+	}
+	return nil
+}
+
+func TestRedaction(t *testing.T) {
+	req := httptest.NewRequest("GET", "/debug/echo?x=1", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	req.Header.Set("Cookie", "session=abc; theme=dark")
+	req.Header.Set("X-Internal-Key", "k")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+
+	rep := serveJSON(t, &Handler{Redact: []string{"x-internal-key"}}, req)
+
+	tests := []struct {
+		name, want string
+	}{
+		{"Authorization", "Bearer [REDACTED]"},
+		{"Cookie", "session=[REDACTED]; theme=[REDACTED]"},
+		{"X-Internal-Key", "[REDACTED]"},
+		{"X-Forwarded-For", "10.0.0.1"},
+	}
+	for _, test := range tests {
+		got := header(rep, test.name)
+		if len(got) != 1 || got[0] != test.want {
+			t.Errorf("header %s = %q, expected %q", test.name, got, test.want)
+		}
+	}
+	if got := rep.Form["x"]; len(got) != 1 || got[0] != "1" {
+		t.Errorf("Form[x] = %q, expected [1]", got)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	body := strings.Repeat("a", 100)
+	req := httptest.NewRequest("POST", "/debug/echo", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rep := serveJSON(t, &Handler{MaxBodyBytes: 10}, req)
+	if !rep.Body.Truncated || rep.Body.Size != 10 {
+		t.Errorf("body = %+v, expected 10 bytes and truncated", rep.Body)
+	}
+	if rep.Form != nil {
+		t.Errorf("Form = %v, expected no form from a truncated body", rep.Form)
+	}
+}
+
+func TestMultipartFiles(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("name", "gopher")
+	fw, _ := mw.CreateFormFile("upload", "notes.txt")
+	fw.Write([]byte("hello, world"))
+	mw.Close()
+
+	req := httptest.NewRequest("POST", "/debug/echo", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	rep := serveJSON(t, New(), req)
+	if len(rep.Files) != 1 {
+		t.Fatalf("Files = %+v, expected one file", rep.Files)
+	}
+	if f := rep.Files[0]; f.Field != "upload" || f.Filename != "notes.txt" || f.Size != 12 {
+		t.Errorf("file = %+v, expected upload/notes.txt/12 bytes", f)
+	}
+	if got := rep.Form["name"]; len(got) != 1 || got[0] != "gopher" {
+		t.Errorf("Form[name] = %q, expected [gopher]", got)
+	}
+	if rep.Body.Text != "" {
+		t.Errorf("multipart body was echoed: %q", rep.Body.Text)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		query, accept, want string
+	}{
+		{"", "", "text/plain"},
+		{"", "text/html,application/xhtml+xml", "text/html"},
+		{"?format=json", "text/html", "application/json"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/debug/echo"+test.query, nil)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		rec := httptest.NewRecorder()
+		New().ServeHTTP(rec, req)
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, test.want) {
+			t.Errorf("%q with Accept %q: Content-Type = %q, expected %s", test.query, test.accept, ct, test.want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module GoBookSolutions/1.12
+
+go 1.20
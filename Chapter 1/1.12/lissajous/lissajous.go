@@ -0,0 +1,65 @@
+// Package lissajous holds the animated Lissajous figure from 1.12 so that it can be
+// mounted by any server, not just the one in this directory.
+
+package lissajous
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultCycles = 5  // number of complete x oscillator revolutions
+	MaxCycles     = 50 // upper bound accepted from a request, to keep rendering cheap
+)
+
+// Lissajous writes the animation to 'out'. The 'cycles' parameter controls how many
+// revolutions of the x oscillator are drawn in every frame.
+func Lissajous(out io.Writer, cycles int) error {
+	const (
+		res     = 0.001
+		size    = 100
+		nframes = 64
+		delay   = 8
+	)
+
+	freq := rand.Float64() * 3.0
+	anim := gif.GIF{LoopCount: nframes}
+	phase := 0.0
+
+	palette := []color.Color{color.White, color.Black}
+
+	for i := 0; i < nframes; i++ {
+		rect := image.Rect(0, 0, 2*size+1, 2*size+1)
+		img := image.NewPaletted(rect, palette)
+		for t := 0.0; t < float64(cycles)*2*math.Pi; t += res { // we need to cast 'cycles' to a 'floor64' because of the 'math' package that expect 'float64' inputs
+			x := math.Sin(t)
+			y := math.Sin(t*freq + phase)
+			img.SetColorIndex(size+int(x*size+0.5), size+int(y*size+0.5), 1)
+		}
+		phase += 0.1
+		anim.Delay = append(anim.Delay, delay)
+		anim.Image = append(anim.Image, img)
+	}
+
+	return gif.EncodeAll(out, &anim)
+}
+
+// Handler serves the animation as 'image/gif'. The 'cycles' form value picks the
+// number of cycles; missing, invalid or out of range values fall back to the default.
+type Handler struct{}
+
+func (Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cycles, err := strconv.Atoi(r.FormValue("cycles")) // here we used 'strconv.Atoi' function to convert the string parameter to an integer
+	if err != nil || cycles <= 0 || cycles > MaxCycles {
+		cycles = DefaultCycles
+	}
+	w.Header().Set("Content-Type", "image/gif")
+	Lissajous(w, cycles)
+}
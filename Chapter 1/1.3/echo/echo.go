@@ -0,0 +1,219 @@
+// Package echo grows the echo programs of 1.1 to 1.3, which only join 'os.Args' with
+// a space, into the POSIX and GNU echo feature set, plus two extensions: a custom
+// separator and a NUL-terminated mode.
+//
+// Options follow GNU echo rather than the 'flag' package: only leading arguments
+// made entirely of option letters are options, and the first argument that is not
+// one (including '--' and a lone '-') starts the operands. That way 'echo -x' and
+// 'echo -- -n' print their arguments, like the shell builtin does.
+//
+//	-n      do not output the trailing newline
+//	-e      interpret backslash escapes
+//	-E      do not interpret backslash escapes (default)
+//	-0      terminate the output with NUL instead of a newline
+//	-s SEP  separate the operands with SEP instead of a space
+//
+// The escapes understood by -e are '\\', '\a', '\b', '\c' (produce no further
+// output, not even the terminator), '\e', '\f', '\n', '\r', '\t', '\v', '\0NNN'
+// (octal, up to three digits), '\NNN' (octal, one to three digits, the first not 0)
+// and '\xHH' (hex, up to two digits). A backslash followed by anything else is
+// printed as is. With -e the separator is unescaped
+// too, so 'echo -e -s "\t" a b' separates the operands with a tab.
+
+package echo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Options controls how the operands are written.
+type Options struct {
+	NoNewline bool   // -n
+	Escapes   bool   // -e, turned off again by -E
+	Null      bool   // -0
+	Sep       string // -s, " " when set by ParseArgs
+}
+
+// ErrMissingSeparator is returned by ParseArgs when '-s' is the last argument.
+var ErrMissingSeparator = errors.New("echo: option -s requires a separator argument")
+
+// ParseArgs splits 'args' (without the program name) into options and operands.
+func ParseArgs(args []string) (Options, []string, error) {
+	opts := Options{Sep: " "}
+	i := 0
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || !isOptionWord(arg[1:]) {
+			break
+		}
+		for _, c := range arg[1:] {
+			switch c {
+			case 'n':
+				opts.NoNewline = true
+			case 'e':
+				opts.Escapes = true
+			case 'E':
+				opts.Escapes = false
+			case '0':
+				opts.Null = true
+			case 's':
+				if i+1 == len(args) {
+					return opts, nil, ErrMissingSeparator
+				}
+				i++
+				opts.Sep = args[i]
+			}
+		}
+	}
+	return opts, args[i:], nil
+}
+
+// isOptionWord reports whether every letter of 'w' is an option letter. An 's' is
+// only allowed last, since it takes the following argument as its value.
+func isOptionWord(w string) bool {
+	for i, c := range w {
+		switch c {
+		case 'n', 'e', 'E', '0':
+		case 's':
+			if i != len(w)-1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Write writes the operands to 'w' as described by 'opts'.
+func Write(w io.Writer, opts Options, operands []string) error {
+	var buf bytes.Buffer
+	stopped := false
+	for i, arg := range operands {
+		if i > 0 {
+			if stopped = appendArg(&buf, opts.Sep, opts.Escapes); stopped {
+				break
+			}
+		}
+		if stopped = appendArg(&buf, arg, opts.Escapes); stopped {
+			break
+		}
+	}
+	if !stopped && !opts.NoNewline {
+		if opts.Null {
+			buf.WriteByte(0)
+		} else {
+			buf.WriteByte('\n')
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Main parses 'args' and writes the result to 'w'.
+func Main(w io.Writer, args []string) error {
+	opts, operands, err := ParseArgs(args)
+	if err != nil {
+		return err
+	}
+	return Write(w, opts, operands)
+}
+
+func appendArg(buf *bytes.Buffer, s string, escapes bool) (stop bool) {
+	if !escapes || !strings.Contains(s, `\`) {
+		buf.WriteString(s)
+		return false
+	}
+	return Unescape(buf, s)
+}
+
+// Unescape writes 's' to 'buf' with its backslash escapes interpreted. It reports
+// whether a '\c' was found, in which case nothing after it was written.
+func Unescape(buf *bytes.Buffer, s string) (stop bool) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			buf.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case '\\':
+			buf.WriteByte('\\')
+		case 'a':
+			buf.WriteByte('\a')
+		case 'b':
+			buf.WriteByte('\b')
+		case 'c':
+			return true
+		case 'e':
+			buf.WriteByte(0x1b)
+		case 'f':
+			buf.WriteByte('\f')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case 'v':
+			buf.WriteByte('\v')
+		case '0':
+			// '\0' followed by up to three octal digits. Values above 0377 wrap
+			// around to a byte, as in GNU echo.
+			var v byte
+			for n := 0; n < 3 && i+1 < len(s) && isOctal(s[i+1]); n++ {
+				i++
+				v = v*8 + s[i] - '0'
+			}
+			buf.WriteByte(v)
+		case '1', '2', '3', '4', '5', '6', '7':
+			// '\NNN', one to three octal digits without the leading 0, which GNU
+			// echo also takes: '\1010' is "A0".
+			v := s[i] - '0'
+			for n := 1; n < 3 && i+1 < len(s) && isOctal(s[i+1]); n++ {
+				i++
+				v = v*8 + s[i] - '0'
+			}
+			buf.WriteByte(v)
+		case 'x':
+			if i+1 == len(s) || !isHex(s[i+1]) {
+				buf.WriteString(`\x`)
+				break
+			}
+			var v byte
+			for n := 0; n < 2 && i+1 < len(s) && isHex(s[i+1]); n++ {
+				i++
+				v = v*16 + hexVal(s[i])
+			}
+			buf.WriteByte(v)
+		default:
+			buf.WriteByte('\\')
+			buf.WriteByte(s[i])
+		}
+	}
+	return false
+}
+
+func isOctal(c byte) bool { return '0' <= c && c <= '7' }
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func hexVal(c byte) byte {
+	switch {
+	case c <= '9':
+		return c - '0'
+	case c >= 'a':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// Usage is printed by the command when the arguments cannot be parsed.
+const Usage = "usage: echo [-neE0] [-s SEP] [STRING]..."
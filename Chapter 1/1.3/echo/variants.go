@@ -0,0 +1,33 @@
+package echo
+
+import "strings"
+
+// Echo1, Echo2 and Echo3 are the three versions of echo from section 1.2 of the book,
+// turned into functions so that they can be benchmarked against each other. Each
+// returns its arguments (without the program name) joined by a space.
+
+// Echo1 concatenates with '+=' inside an indexed loop.
+func Echo1(args []string) string {
+	var s, sep string
+	for i := 0; i < len(args); i++ {
+		s += sep + args[i]
+		sep = " "
+	}
+	return s
+}
+
+// Echo2 concatenates with '+=' inside a 'range' loop.
+func Echo2(args []string) string {
+	s, sep := "", ""
+	for _, arg := range args {
+		s += sep + arg
+		sep = " "
+	}
+	return s
+}
+
+// Echo3 uses 'strings.Join', which sizes the result once and copies every
+// argument into it a single time.
+func Echo3(args []string) string {
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,66 @@
+// These benchmarks replace the single 'time.Now' measurement that 1.3 used to make.
+// Every variant is run for argument counts from 1 to 100k and reports allocations
+// per operation. The sub-benchmark names use the 'key=value' form, so the output of
+//
+//	go test ./echo -run '^$' -bench Echo -benchmem -count 10 > new.txt
+//	benchstat -col /impl new.txt
+//
+// puts the three implementations next to each other for every argument count. The
+// results we got are in 'bench_results.txt' next to 1.3.go.
+
+package echo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var variants = []struct {
+	name string
+	fn   func([]string) string
+}{
+	{"echo1", Echo1},
+	{"echo2", Echo2},
+	{"echo3", Echo3},
+}
+
+// argCounts goes up to 100k arguments, where the quadratic copying of the two
+// concatenating versions dominates.
+var argCounts = []int{1, 10, 100, 1000, 10000, 100000}
+
+func makeArgs(n int) []string {
+	args := make([]string, n)
+	for i := range args {
+		args[i] = fmt.Sprintf("arg%d", i%1000)
+	}
+	return args
+}
+
+func TestVariantsAgree(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 57} {
+		args := makeArgs(n)
+		want := strings.Join(args, " ")
+		for _, v := range variants {
+			if got := v.fn(args); got != want {
+				t.Errorf("%s with %d args = %q, expected %q", v.name, n, got, want)
+			}
+		}
+	}
+}
+
+var sink string
+
+func BenchmarkEcho(b *testing.B) {
+	for _, n := range argCounts {
+		args := makeArgs(n)
+		for _, v := range variants {
+			b.Run(fmt.Sprintf("args=%d/impl=%s", n, v.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					sink = v.fn(args)
+				}
+			})
+		}
+	}
+}
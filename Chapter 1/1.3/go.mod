@@ -0,0 +1,3 @@
+module GoBookSolutions/1.3
+
+go 1.20
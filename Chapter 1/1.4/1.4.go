@@ -1,47 +1,137 @@
 /*To get the program to print out the names of the files that
-have duplicate lines, we changed the map definition to 'map[string][]string',
-which maps each line to a slice of filenames. We also added an argument
-called 'filename' to the 'countLines' function, indicative of the names of
-files being scanned. We then modified the map in the function to append the
-'filename' to the slice of filenames associated with each line. Lastly, we
-modified the 'for' loop so that it also prints out the names of the files,
-using 'range' to iterate over the keys and values of the 'counts' map.*/
+have duplicate lines, we first changed the map definition to 'map[string][]string',
+which maps each line to a slice of filenames. That made a line that appears three
+times in one file look like a line shared by three files, and it lost the line
+numbers. The map is now an 'index' (index.go) from each line to all of its
+occurrences, where an occurrence is a file and a line number. The 'countLines'
+function still scans one file at a time, but it records the line number of every
+line it reads.
+
+The report shows, for every line that occurs more than once, the total count and
+then how many times and on which lines it appears in each file. It is sorted by
+count and then by text instead of following the random order of the map. With
+'-cross' only lines shared by at least two files are reported.
+
+Lines can also be matched fuzzily, to find log lines and copy-pasted code that only
+differ cosmetically. The flags can be combined (see normalize.go for the order they
+are applied in):
+
+	-fold          Unicode case folding
+	-trim          ignore leading and trailing whitespace
+	-collapse      treat any run of whitespace as one space
+	-noindent      ignore leading indentation only
+	-norm nfc      compare in Unicode NFC (or nfkc) form
+	-strip REGEX   remove the matches of REGEX first, e.g.
+	               -strip '^\d{4}-\d\d-\d\dT[0-9:.]+Z? '
+
+The report shows the first of the lines that matched.
+
+For inputs larger than memory, '-hash 64' (or '-hash 128') keeps only line hashes,
+spills them to sorted files in '-tmpdir' once '-mem' MB are used, and then reads the
+inputs again to recover the text of the duplicated lines. See external.go.
+
+With '-block N' dup looks for copy-pasted code instead: runs of N or more consecutive
+lines that appear more than once, reported as groups of file:line ranges. The
+normalization flags apply to every line of the blocks. See clone.go.
+
+Directories are walked with '-r', filtered with '-include' and '-exclude' globs and,
+with '-gitignore', by the '.gitignore' files found on the way (see walk.go). Files
+that look binary are skipped unless '-binary' is given. The files are read by '-j'
+goroutines at once, each into an index of its own that is then merged into the
+shared one, and the report is the same whatever order they finish in (parallel.go).*/
 
 package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
 )
 
 func main() {
-	counts := make(map[string][]string)
-	files := os.Args[1:]
+	var n normalizer
+	cross := flag.Bool("cross", false, "only report lines that appear in more than one file")
+	flag.BoolVar(&n.fold, "fold", false, "compare lines with Unicode case folding")
+	flag.BoolVar(&n.trim, "trim", false, "ignore leading and trailing whitespace")
+	flag.BoolVar(&n.collapse, "collapse", false, "treat runs of whitespace as a single space (implies -trim)")
+	flag.BoolVar(&n.noIndent, "noindent", false, "ignore leading indentation")
+	flag.Var(&n.strip, "strip", "remove matches of this regular expression before comparing (repeatable)")
+	form := flag.String("norm", "", "Unicode normalization form to compare in: nfc or nfkc")
+	hashBits := flag.Int("hash", 0, "store 64- or 128-bit line hashes instead of lines, for inputs larger than memory")
+	memMB := flag.Int64("mem", 64, "memory budget in MB for the -hash mode before it spills to disk")
+	tmpDir := flag.String("tmpdir", "", "directory for the -hash mode's temporary files (default: system temp dir)")
+	block := flag.Int("block", 0, "report repeated blocks of at least this many consecutive lines instead of single lines")
+	var walk walkOptions
+	flag.BoolVar(&walk.recursive, "r", false, "walk directories recursively")
+	flag.Var(&walk.include, "include", "only read walked files matching this glob (repeatable)")
+	flag.Var(&walk.exclude, "exclude", "skip walked files and directories matching this glob (repeatable)")
+	flag.BoolVar(&walk.gitignore, "gitignore", false, "skip what .gitignore files in walked directories ignore")
+	binary := flag.Bool("binary", false, "also read files that look binary (contain a NUL byte)")
+	workers := flag.Int("j", runtime.NumCPU(), "number of files read concurrently")
+	flag.Parse()
+	if err := n.setForm(*form); err != nil {
+		fmt.Fprintf(os.Stderr, "dup2: %v\n", err)
+		os.Exit(2)
+	}
+	if *block != 0 && (*block < 2 || *hashBits != 0) {
+		fmt.Fprintln(os.Stderr, "dup2: -block needs at least 2 lines and cannot be combined with -hash")
+		os.Exit(2)
+	}
+
+	var files []string
+	if args := flag.Args(); len(args) > 0 {
+		if files = collectFiles(args, walk, os.Stderr); len(files) == 0 {
+			os.Exit(1)
+		}
+	}
+	if *hashBits != 0 {
+		opts := hashOptions{bits: *hashBits, mem: *memMB << 20, dir: *tmpDir, binary: *binary, stdin: os.Stdin}
+		counts, _, err := dupHashed(files, &n, opts, os.Stderr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dup2: %v\n", err)
+			os.Exit(1)
+		}
+		report(os.Stdout, counts.duplicates(*cross))
+		return
+	}
+
+	counts := newIndex(&n)
+	if *block != 0 {
+		counts = newBlockIndex(&n)
+	}
 	if len(files) == 0 {
-		countLines(os.Stdin, counts, "stdin")
-	} else {
-		for _, arg := range files {
-			f, err := os.Open(arg)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "dup2: %v\n", err)
-				continue
-			}
-			countLines(f, counts, arg)
-			f.Close()
+		if err := countLines(os.Stdin, counts, "stdin"); err != nil {
+			fmt.Fprintf(os.Stderr, "dup2: %v\n", err)
 		}
+	} else {
+		scanFiles(files, counts, *workers, *binary, os.Stderr)
 	}
-	for line, files := range counts {
-		if len(files) > 1 {
-			fmt.Printf("%d\t%s\t%v\n", len(files), line, files)
-		} 
+	if *block != 0 {
+		reportClones(os.Stdout, counts.files, findClones(counts.seqs, *block))
+		return
 	}
+	report(os.Stdout, counts.duplicates(*cross))
 }
 
-func countLines(f *os.File, counts map[string][]string, filename string) {
+func countLines(f io.Reader, counts *index, filename string) error {
+	return scanLines(f, counts, counts.addFile(filename))
+}
+
+// scanLines adds the lines of 'f' to 'counts' as those of file number 'file'.
+func scanLines(f io.Reader, counts *index, file int) error {
 	input := bufio.NewScanner(f)
+	input.Buffer(nil, maxLineSize)
+	lineNo := 0
 	for input.Scan() {
-		line := input.Text()
-		counts[line] = append(counts[line], filename)
+		lineNo++
+		counts.add(file, lineNo, input.Text())
 	}
+	return input.Err()
 }
+
+// maxLineSize raises the 64KB line limit of 'bufio.Scanner', which minified files
+// and some logs exceed.
+const maxLineSize = 16 << 20
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+/* Block mode ('-block N') looks for runs of N or more consecutive lines that appear
+more than once, the way copy-paste detectors do. 'countLines' still walks the files,
+but the index turns every line into the number of its (normalized) key, so that two
+lines are equal exactly when their numbers are.
+
+ 1. A rolling hash (Rabin-Karp) is computed over every window of N line numbers,
+    and windows are bucketed by hash. Sliding the window one line costs one
+    multiplication, one subtraction and one addition, whatever N is.
+ 2. A bucket is split into classes of windows with the same numbers, so a hash
+    collision can put different windows in one bucket but never in one class.
+ 3. A class is refined like a trie: its first window is compared with the others,
+    a run of identical lines at a time, and the windows that share as many lines
+    with it, or that differ from it at the same line but not from each other, form
+    smaller classes that are refined in turn. A window is thus compared with one
+    window of each class it is in, not with all of them.
+ 4. Two windows that share exactly n lines are two copies of a clone of n lines,
+    unless they also follow the same line, because the clone then starts earlier,
+    or they are less than n lines apart in one file, so that a run of identical
+    lines is not reported as a clone of itself shifted by one. Whether a window has
+    such a partner is told from counts by file and previous line.
+ 5. The copies found for the same lines form a clone group, which is reported with
+    the file:line range of every copy. */
+
+// rollBase is the multiplier of the rolling hash. Arithmetic is modulo 2^64.
+const rollBase = 1000003
+
+// pos is the start of a window: a file and a 0-based line index.
+type pos struct {
+	file, i int
+}
+
+// span is one copy of a clone, with 1-based inclusive line numbers.
+type span struct {
+	file, start, end int
+}
+
+// cloneGroup is a block of 'length' lines that appears at every span.
+type cloneGroup struct {
+	length int
+	spans  []span
+}
+
+// findClones returns the clone groups of at least 'minLen' lines in 'seqs', longest
+// first. Groups of equal length are ordered by their number of copies and then by
+// the position of their first copy.
+func findClones(seqs [][]uint32, minLen int) []cloneGroup {
+	if minLen < 1 {
+		minLen = 1
+	}
+	var pow uint64 = 1 // rollBase^(minLen-1), to drop the line leaving the window
+	for k := 1; k < minLen; k++ {
+		pow *= rollBase
+	}
+
+	buckets := make(map[uint64][]pos)
+	for f, seq := range seqs {
+		if len(seq) < minLen {
+			continue
+		}
+		var h uint64
+		for i, id := range seq {
+			if i >= minLen {
+				h -= uint64(seq[i-minLen]+1) * pow
+			}
+			h = h*rollBase + uint64(id+1)
+			if i >= minLen-1 {
+				start := i - minLen + 1
+				buckets[h] = append(buckets[h], pos{f, start})
+			}
+		}
+	}
+
+	f := cloneFinder{seqs: seqs, runs: make([][]int, len(seqs))}
+	for i, seq := range seqs {
+		f.runs[i] = runLengths(seq)
+	}
+	var stack []refineTask
+	for _, ps := range buckets {
+		if len(ps) < 2 {
+			continue
+		}
+		for _, class := range windowClasses(seqs, ps, minLen) {
+			stack = append(stack, refineTask{class, minLen, nil})
+		}
+	}
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = f.refine(t, stack[:len(stack)-1])
+	}
+
+	var out []cloneGroup
+	for _, set := range f.groups {
+		if len(set.spans) == 0 {
+			continue
+		}
+		g := cloneGroup{length: set.length}
+		for s := range set.spans {
+			g.spans = append(g.spans, s)
+		}
+		sort.Slice(g.spans, func(i, j int) bool {
+			if g.spans[i].file != g.spans[j].file {
+				return g.spans[i].file < g.spans[j].file
+			}
+			return g.spans[i].start < g.spans[j].start
+		})
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.length != b.length {
+			return a.length > b.length
+		}
+		if len(a.spans) != len(b.spans) {
+			return len(a.spans) > len(b.spans)
+		}
+		if a.spans[0].file != b.spans[0].file {
+			return a.spans[0].file < b.spans[0].file
+		}
+		return a.spans[0].start < b.spans[0].start
+	})
+	return out
+}
+
+// windowClasses splits the windows 'ps' of one bucket into classes of windows with
+// the same 'minLen' numbers. Classes of a single window are left out.
+func windowClasses(seqs [][]uint32, ps []pos, minLen int) [][]pos {
+	var classes [][]pos
+next:
+	for _, p := range ps {
+		w := seqs[p.file][p.i : p.i+minLen]
+		for c, class := range classes {
+			r := class[0]
+			if equalIDs(w, seqs[r.file][r.i:r.i+minLen]) {
+				classes[c] = append(class, p)
+				continue next
+			}
+		}
+		classes = append(classes, []pos{p})
+	}
+	out := classes[:0]
+	for _, class := range classes {
+		if len(class) > 1 {
+			out = append(out, class)
+		}
+	}
+	return out
+}
+
+func equalIDs(a, b []uint32) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// runLengths returns, for every line of 'seq', the number of identical lines that
+// start with it.
+func runLengths(seq []uint32) []int {
+	runs := make([]int, len(seq))
+	for i := len(seq) - 1; i >= 0; i-- {
+		runs[i] = 1
+		if i+1 < len(seq) && seq[i+1] == seq[i] {
+			runs[i] += runs[i+1]
+		}
+	}
+	return runs
+}
+
+// cloneFinder holds the state of 'findClones' while classes are refined.
+type cloneFinder struct {
+	seqs   [][]uint32
+	runs   [][]int // the 'runLengths' of every file
+	groups []*groupSet
+}
+
+// groupSet collects the copies of a block of 'length' lines.
+type groupSet struct {
+	length int
+	spans  map[span]bool
+}
+
+// refineTask is a class of windows that share their first 'depth' lines. 'group'
+// collects the copies of those lines, or is nil when the lines have no group yet.
+type refineTask struct {
+	ws    []pos
+	depth int
+	group *groupSet
+}
+
+func (f *cloneFinder) newGroup(length int) *groupSet {
+	g := &groupSet{length: length, spans: make(map[span]bool)}
+	f.groups = append(f.groups, g)
+	return g
+}
+
+func (g *groupSet) add(p pos) {
+	g.spans[span{p.file, p.i + 1, p.i + g.length}] = true
+}
+
+// prev returns the line before the window 'p', or -1 if 'p' starts its file.
+func (f *cloneFinder) prev(p pos) int64 {
+	if p.i == 0 {
+		return -1
+	}
+	return int64(f.seqs[p.file][p.i-1])
+}
+
+// common returns the number of lines that the windows 'p' and 'q', known to share
+// their first 'n' lines, have in common. Where both are in a run of the same line,
+// we skip to the end of the shorter run.
+func (f *cloneFinder) common(p, q pos, n int) int {
+	a, b := f.seqs[p.file], f.seqs[q.file]
+	for p.i+n < len(a) && q.i+n < len(b) && a[p.i+n] == b[q.i+n] {
+		step := f.runs[p.file][p.i+n]
+		if r := f.runs[q.file][q.i+n]; r < step {
+			step = r
+		}
+		n += step
+	}
+	return n
+}
+
+// refine compares the first window of 't' with the others and collects the copies
+// found at each length they share with it. The windows that may still share more
+// lines with each other are pushed on 'stack' as smaller tasks.
+func (f *cloneFinder) refine(t refineTask, stack []refineTask) []refineTask {
+	r, d := t.ws[0], t.depth
+	common := make([]int, len(t.ws))
+	rest := newWindowSet()
+	rest.add(r, f.prev(r))
+	var diverge []pos // windows that differ from 'r' right after the 'd' lines
+	var deeper []int  // indexes of the windows that share more lines with 'r'
+	for k := 1; k < len(t.ws); k++ {
+		p := t.ws[k]
+		common[k] = f.common(r, p, d)
+		if common[k] == d {
+			diverge = append(diverge, p)
+		} else {
+			rest.add(p, f.prev(p))
+			deeper = append(deeper, k)
+		}
+	}
+	if len(diverge) > 0 {
+		g := t.group
+		if g == nil {
+			g = f.newGroup(d)
+		}
+		parts := f.byNextLine(diverge, d)
+		f.collect(g, parts, rest)
+		for _, part := range parts {
+			if len(part) > 1 {
+				stack = append(stack, refineTask{part, d + 1, nil})
+			}
+		}
+	}
+
+	// A window that shares n lines with 'r' shares exactly n lines with every
+	// window that shares more, so the windows are taken by increasing n and
+	// removed from 'rest' on the way.
+	sort.SliceStable(deeper, func(a, b int) bool { return common[deeper[a]] < common[deeper[b]] })
+	for a := 0; a < len(deeper); {
+		n := common[deeper[a]]
+		var ws []pos
+		for ; a < len(deeper) && common[deeper[a]] == n; a++ {
+			p := t.ws[deeper[a]]
+			rest.remove(p, f.prev(p))
+			ws = append(ws, p)
+		}
+		g := f.newGroup(n)
+		f.collect(g, [][]pos{ws}, rest)
+		if len(ws) > 1 {
+			stack = append(stack, refineTask{ws, n, g})
+		}
+	}
+	return stack
+}
+
+// byNextLine splits windows that share their first 'n' lines by the line after
+// them. A window that ends its file after 'n' lines is a part of its own.
+func (f *cloneFinder) byNextLine(ws []pos, n int) [][]pos {
+	var parts [][]pos
+	index := make(map[uint32]int)
+	for _, p := range ws {
+		seq := f.seqs[p.file]
+		if p.i+n == len(seq) {
+			parts = append(parts, []pos{p})
+			continue
+		}
+		k, ok := index[seq[p.i+n]]
+		if !ok {
+			k = len(parts)
+			index[seq[p.i+n]] = k
+			parts = append(parts, nil)
+		}
+		parts[k] = append(parts[k], p)
+	}
+	return parts
+}
+
+// collect adds to 'g' the copies of its lines among windows that share exactly
+// g.length lines: pairs of windows from different 'parts', and pairs of a window
+// of 'parts' with one of 'rest'. A pair counts if the windows do not also follow
+// the same line and, in one file, are at least g.length lines apart. 'rest' may be
+// much larger than 'parts', so it is only searched for windows with a partner.
+func (f *cloneFinder) collect(g *groupSet, parts [][]pos, rest *windowSet) {
+	all := newWindowSet()
+	for _, part := range parts {
+		for _, p := range part {
+			all.add(p, f.prev(p))
+		}
+	}
+	for _, part := range parts {
+		var own *windowSet // the part itself, when other parts are in 'all'
+		if len(parts) > 1 && len(part) > 1 {
+			own = newWindowSet()
+			for _, p := range part {
+				own.add(p, f.prev(p))
+			}
+		}
+		for _, p := range part {
+			prev := f.prev(p)
+			others := 0
+			if len(parts) > 1 {
+				others = all.elsewhere(p, prev) - own.elsewhere(p, prev)
+			}
+			if rest.elsewhere(p, prev) > 0 || others > 0 ||
+				rest.apart(p, prev, g.length, nil) ||
+				len(parts) > 1 && all.apart(p, prev, g.length, own) {
+				g.add(p)
+			}
+		}
+	}
+
+	// If the parts follow a single line, only the windows of 'rest' that follow
+	// another line can pair with them.
+	_, starts := all.byPrev[-1]
+	skip, single := int64(0), !starts && len(all.byPrev) == 1
+	for prev := range all.byPrev {
+		skip = prev
+	}
+	for file, byPrev := range rest.byFile {
+		for prev, is := range byPrev {
+			if single && prev == skip {
+				continue
+			}
+			for i := range is {
+				q := pos{file, i}
+				if all.elsewhere(q, prev) > 0 || all.apart(q, prev, g.length, nil) {
+					g.add(q)
+				}
+			}
+		}
+	}
+}
+
+// windowSet counts windows by file and by the line before them, which is -1 at the
+// start of a file, to tell whether a window has a partner without trying each one.
+type windowSet struct {
+	n      int
+	inFile map[int]int
+	byPrev map[int64]int
+	byFile map[int]map[int64]map[int]bool // file, previous line, start
+}
+
+func newWindowSet() *windowSet {
+	return &windowSet{
+		inFile: make(map[int]int),
+		byPrev: make(map[int64]int),
+		byFile: make(map[int]map[int64]map[int]bool),
+	}
+}
+
+func (s *windowSet) add(p pos, prev int64) {
+	s.n++
+	s.inFile[p.file]++
+	s.byPrev[prev]++
+	byPrev := s.byFile[p.file]
+	if byPrev == nil {
+		byPrev = make(map[int64]map[int]bool)
+		s.byFile[p.file] = byPrev
+	}
+	if byPrev[prev] == nil {
+		byPrev[prev] = make(map[int]bool)
+	}
+	byPrev[prev][p.i] = true
+}
+
+func (s *windowSet) remove(p pos, prev int64) {
+	s.n--
+	if s.inFile[p.file]--; s.inFile[p.file] == 0 {
+		delete(s.inFile, p.file)
+	}
+	if s.byPrev[prev]--; s.byPrev[prev] == 0 {
+		delete(s.byPrev, prev)
+	}
+	byPrev := s.byFile[p.file]
+	delete(byPrev[prev], p.i)
+	if len(byPrev[prev]) == 0 {
+		delete(byPrev, prev)
+	}
+	if len(byPrev) == 0 {
+		delete(s.byFile, p.file)
+	}
+}
+
+// elsewhere returns the number of windows of the set in other files than 'p' that
+// do not follow the same line as 'p'. A nil set has none.
+func (s *windowSet) elsewhere(p pos, prev int64) int {
+	if s == nil {
+		return 0
+	}
+	n := s.n - s.inFile[p.file]
+	if prev >= 0 {
+		n -= s.byPrev[prev] - len(s.byFile[p.file][prev])
+	}
+	return n
+}
+
+// apart reports whether the set has a window in the file of 'p' that does not
+// follow the same line, is at least 'n' lines away from 'p' and is not in 'skip'.
+func (s *windowSet) apart(p pos, prev int64, n int, skip *windowSet) bool {
+	for q, is := range s.byFile[p.file] {
+		if prev >= 0 && q == prev {
+			continue
+		}
+		for i := range is {
+			if (i-p.i >= n || p.i-i >= n) && (skip == nil || !skip.byFile[p.file][q][i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reportClones prints each clone group as its length and number of copies,
+// followed by the file:line range of every copy:
+//
+//	12 lines	3 copies
+//		a.go:10-21
+//		a.go:40-51
+//		b.go:5-16
+func reportClones(w io.Writer, files []string, groups []cloneGroup) {
+	for _, g := range groups {
+		fmt.Fprintf(w, "%d lines\t%d copies\n", g.length, len(g.spans))
+		for _, s := range g.spans {
+			fmt.Fprintf(w, "\t%s:%d-%d\n", files[s.file], s.start, s.end)
+		}
+	}
+}
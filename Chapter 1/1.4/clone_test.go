@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindClones(t *testing.T) {
+	idx := newBlockIndex(&normalizer{noIndent: true})
+	scan(t, idx,
+		"a.go", "package a\nfunc f() {\n\tx := 1\n\ty := 2\n\treturn x + y\n}\n// end\n",
+		"b.go", "package b\n\n// copy\nfunc f() {\n    x := 1\n    y := 2\n    return x + y\n}\n",
+		"c.go", "x := 1\ny := 2\nz := 3\n",
+	)
+
+	var buf bytes.Buffer
+	reportClones(&buf, idx.files, findClones(idx.seqs, 3))
+	want := "5 lines\t2 copies\n" +
+		"\ta.go:2-6\n" +
+		"\tb.go:4-8\n"
+	if buf.String() != want {
+		t.Errorf("clones:\n%s\nexpected:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	reportClones(&buf, idx.files, findClones(idx.seqs, 2))
+	if !strings.Contains(buf.String(), "2 lines\t3 copies\n\ta.go:3-4\n\tb.go:5-6\n\tc.go:1-2\n") {
+		t.Errorf("expected the 2-line block in all three files, got:\n%s", buf.String())
+	}
+}
+
+func TestClonesDoNotOverlap(t *testing.T) {
+	idx := newBlockIndex(nil)
+	scan(t, idx, "a.txt", strings.Repeat("same\n", 6))
+
+	groups := findClones(idx.seqs, 2)
+	for _, g := range groups {
+		for i := 1; i < len(g.spans); i++ {
+			if g.spans[i].start <= g.spans[i-1].end {
+				t.Errorf("overlapping copies %v and %v", g.spans[i-1], g.spans[i])
+			}
+		}
+	}
+	if len(groups) == 0 || groups[0].length != 3 {
+		t.Errorf("groups = %v, expected the two halves as a 3-line clone first", groups)
+	}
+}
+
+// TestLongRun checks that a long run of identical lines, whose windows all fall in one
+// bucket, is refined in linear rather than quadratic time.
+func TestLongRun(t *testing.T) {
+	const n = 100000
+	idx := newBlockIndex(nil)
+	scan(t, idx, "a.txt", "first\n"+strings.Repeat("same\n", n)+"last\n", "b.txt", "same\nsame\nsame\n")
+
+	groups := findClones(idx.seqs, 3)
+	if len(groups) == 0 || groups[0].length != n/2 {
+		t.Fatalf("expected the two halves of the run as the longest clone, got %d groups", len(groups))
+	}
+	want := []span{{0, 2, n/2 + 1}, {0, n/2 + 2, n + 1}}
+	if got := groups[0].spans; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("spans = %v, expected %v", got, want)
+	}
+	for _, g := range groups {
+		if g.length == 3 && len(g.spans) < 3 {
+			t.Errorf("the 3-line block of b.txt is in %d copies, expected it in a.txt too", len(g.spans))
+		}
+	}
+}
+
+// TestHashCollision checks that blocks with the same rolling hash but different
+// lines are not merged: the Thue-Morse sequence and its complement collide for any
+// odd base modulo 2^64 once they are long enough.
+func TestHashCollision(t *testing.T) {
+	const n = 2048
+	var tm, co strings.Builder
+	for i := 0; i < n; i++ {
+		ones := 0
+		for x := i; x > 0; x &= x - 1 {
+			ones++
+		}
+		fmt.Fprintf(&tm, "%d\n", ones%2)
+		fmt.Fprintf(&co, "%d\n", 1-ones%2)
+	}
+	idx := newBlockIndex(nil)
+	scan(t, idx, "a.txt", tm.String(), "b.txt", tm.String(), "c.txt", co.String(), "d.txt", co.String())
+
+	groups := findClones(idx.seqs, 8)
+	if len(groups) < 2 || groups[0].length != n || groups[1].length != n {
+		t.Fatalf("expected two groups of %d lines", n)
+	}
+	for _, g := range groups {
+		first := g.spans[0]
+		want := idx.seqs[first.file][first.start-1 : first.end]
+		for _, s := range g.spans[1:] {
+			if !equalIDs(want, idx.seqs[s.file][s.start-1:s.end]) {
+				t.Fatalf("%d-line group has different copies %v and %v", g.length, first, s)
+			}
+		}
+	}
+}
+
+// TestManyPredecessors checks that the windows of a class are not paired with each
+// other when they follow many different lines or start many files, which took
+// quadratic time.
+func TestManyPredecessors(t *testing.T) {
+	const n = 20000
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "x%d := f()\n\treturn nil\n}\n", i)
+	}
+	idx := newBlockIndex(nil)
+	scan(t, idx, "a.go", b.String())
+	var args []string
+	for i := 0; i < n/10; i++ {
+		args = append(args, fmt.Sprintf("f%d.go", i), fmt.Sprintf("// header\npackage p\n\nvar v%d = 1\n", i))
+	}
+	scan(t, idx, args...)
+
+	start := time.Now()
+	groups := findClones(idx.seqs, 2)
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("findClones took %v", d)
+	}
+	if len(groups) != 2 || len(groups[0].spans) != n/10 || len(groups[1].spans) != n {
+		t.Fatalf("expected %d and %d copies of two blocks, got %d groups", n/10, n, len(groups))
+	}
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+)
+
+/* The in-memory index keeps every distinct line string, which runs out of memory on
+multi-GB logs. The hashing mode ('-hash 64' or '-hash 128') never holds the lines
+themselves on its first pass, only a count per line hash:
+
+ 1. Every line is normalized and hashed with FNV-1a. A map from hash to count is
+    kept in memory; when it reaches the '-mem' budget, its entries are sorted and
+    written to a run file in '-tmpdir' and the map starts over.
+ 2. The run files are merged (a k-way merge over sorted runs), adding up the counts
+    of equal hashes. Hashes with a total above one are the duplicates. At most
+    'mergeFanIn' runs are open at once: when there are more, they are first merged
+    in batches into fewer, longer runs, as many times as needed.
+ 3. The inputs are read again with the usual 'countLines' loop, but the index only
+    records lines whose hash is a duplicate, which recovers their text, files and
+    line numbers. The duplicated hashes are held within the same budget as the
+    counts: when the merge has found that many, the inputs are read for them, and
+    the merge goes on with an empty set. Standard input is copied to a temporary
+    file on the first pass so that it can be read more than once.
+
+Only a budget's worth of duplicated hashes, and the occurrences of the duplicates,
+are kept in memory after the first pass. A hash collision can make two different
+lines look like one duplicate, but the second pass groups lines by their actual key,
+so the report is never wrong; '-hash 128' just makes the wasted work less likely on
+inputs with billions of lines. */
+
+// hashKey holds a 64- or 128-bit hash. 64-bit hashes leave the last 8 bytes zero.
+type hashKey [16]byte
+
+// recordSize is the size of a run file record: the hash followed by its count.
+const recordSize = 16 + 8
+
+// mergeFanIn is the most run files merged at once.
+const mergeFanIn = 64
+
+// bytesPerKey is our estimate of what one map entry costs, used to turn the '-mem'
+// budget into a number of entries.
+const bytesPerKey = 64
+
+// lineHasher hashes normalized keys. It reuses its 'hash.Hash' and a buffer between
+// lines, so that hashing does not allocate.
+type lineHasher struct {
+	h   hash.Hash
+	buf []byte
+}
+
+func newLineHasher(bits int) (*lineHasher, error) {
+	switch bits {
+	case 64:
+		return &lineHasher{h: fnv.New64a()}, nil
+	case 128:
+		return &lineHasher{h: fnv.New128a()}, nil
+	}
+	return nil, fmt.Errorf("unsupported hash size %d (want 64 or 128)", bits)
+}
+
+func (lh *lineHasher) sum(key string) hashKey {
+	lh.buf = append(lh.buf[:0], key...)
+	return lh.sumBytes(lh.buf)
+}
+
+func (lh *lineHasher) sumBytes(key []byte) hashKey {
+	var k hashKey
+	lh.h.Reset()
+	lh.h.Write(key)
+	lh.h.Sum(k[:0])
+	return k
+}
+
+// hashCounter counts hashes within a bounded number of map entries, spilling sorted
+// runs to disk when the map is full.
+type hashCounter struct {
+	limit  int
+	dir    string
+	counts map[hashKey]uint64
+	keys   []hashKey // reused by every spill
+	runs   []string
+
+	maxHeld int // the most entries held at once
+	spilled int // the number of runs written by 'spill'
+}
+
+func newHashCounter(limit int, dir string) *hashCounter {
+	if limit < 1 {
+		limit = 1
+	}
+	return &hashCounter{limit: limit, dir: dir, counts: make(map[hashKey]uint64)}
+}
+
+func (c *hashCounter) add(k hashKey) error {
+	c.counts[k]++
+	if n := len(c.counts); n > c.maxHeld {
+		c.maxHeld = n
+	}
+	if len(c.counts) >= c.limit {
+		return c.spill()
+	}
+	return nil
+}
+
+// spill writes the map as a sorted run file and empties it.
+func (c *hashCounter) spill() error {
+	if len(c.counts) == 0 {
+		return nil
+	}
+	keys := c.keys[:0]
+	for k := range c.counts {
+		keys = append(keys, k)
+	}
+	c.keys = keys
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+
+	w, err := c.createRun()
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := w.write(k, c.counts[k]); err != nil {
+			w.close()
+			return err
+		}
+	}
+	c.spilled++
+	// Deleting every key keeps the map's buckets, so the next run reuses them
+	// instead of growing a new map from scratch.
+	for k := range c.counts {
+		delete(c.counts, k)
+	}
+	return w.close()
+}
+
+// createRun creates an empty run file and adds it to the runs to remove.
+func (c *hashCounter) createRun() (*runWriter, error) {
+	f, err := os.CreateTemp(c.dir, "dup-run-*")
+	if err != nil {
+		return nil, err
+	}
+	c.runs = append(c.runs, f.Name())
+	return &runWriter{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// compact merges the runs in batches of 'mergeFanIn' into new runs until no more
+// than 'mergeFanIn' are left, removing the merged ones.
+func (c *hashCounter) compact() error {
+	for len(c.runs) > mergeFanIn {
+		batch := c.runs[:mergeFanIn]
+		c.runs = c.runs[mergeFanIn:]
+		w, err := c.createRun()
+		if err != nil {
+			return err
+		}
+		err = mergeRuns(batch, w.write)
+		if cerr := w.close(); err == nil {
+			err = cerr
+		}
+		for _, name := range batch {
+			os.Remove(name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// duplicates calls 'each' with the hashes counted more than once, in sets of at
+// most as many hashes as the counts were allowed, one set after the other. The
+// counts are dropped first, so that a set takes their place in memory. 'each' is
+// not called if there are no duplicates.
+func (c *hashCounter) duplicates(each func(dups map[hashKey]bool) error) error {
+	dups := make(map[hashKey]bool)
+	if len(c.runs) == 0 {
+		for k, n := range c.counts {
+			if n > 1 {
+				dups[k] = true
+			}
+		}
+		c.counts, c.keys = nil, nil
+		if len(dups) == 0 {
+			return nil
+		}
+		return each(dups)
+	}
+	if err := c.spill(); err != nil {
+		return err
+	}
+	c.counts, c.keys = nil, nil
+	if err := c.compact(); err != nil {
+		return err
+	}
+	err := mergeRuns(c.runs, func(k hashKey, n uint64) error {
+		if n < 2 {
+			return nil
+		}
+		dups[k] = true
+		if len(dups) < c.limit {
+			return nil
+		}
+		err := each(dups)
+		dups = make(map[hashKey]bool)
+		return err
+	})
+	if err != nil || len(dups) == 0 {
+		return err
+	}
+	return each(dups)
+}
+
+// cleanup removes the run files.
+func (c *hashCounter) cleanup() {
+	for _, name := range c.runs {
+		os.Remove(name)
+	}
+	c.runs = nil
+}
+
+// runWriter writes the records of one run file, in the order they are given.
+type runWriter struct {
+	f   *os.File
+	w   *bufio.Writer
+	rec [recordSize]byte
+}
+
+func (rw *runWriter) write(k hashKey, n uint64) error {
+	copy(rw.rec[:16], k[:])
+	binary.BigEndian.PutUint64(rw.rec[16:], n)
+	_, err := rw.w.Write(rw.rec[:])
+	return err
+}
+
+func (rw *runWriter) close() error {
+	err := rw.w.Flush()
+	if cerr := rw.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// runReader reads the records of one run file in order.
+type runReader struct {
+	r   *bufio.Reader
+	key hashKey
+	n   uint64
+}
+
+func (rr *runReader) next() (bool, error) {
+	var rec [recordSize]byte
+	if _, err := io.ReadFull(rr.r, rec[:]); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, err
+	}
+	copy(rr.key[:], rec[:16])
+	rr.n = binary.BigEndian.Uint64(rec[16:])
+	return true, nil
+}
+
+// runHeap orders run readers by their current key.
+type runHeap []*runReader
+
+func (h runHeap) Len() int            { return len(h) }
+func (h runHeap) Less(i, j int) bool  { return bytes.Compare(h[i].key[:], h[j].key[:]) < 0 }
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// mergeRuns merges sorted run files and calls 'emit' once per distinct hash with
+// the sum of its counts, in hash order, until 'emit' returns an error. Every file is
+// open until the merge ends, so callers keep 'names' to 'mergeFanIn' files.
+func mergeRuns(names []string, emit func(hashKey, uint64) error) error {
+	var h runHeap
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rr := &runReader{r: bufio.NewReader(f)}
+		ok, err := rr.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h = append(h, rr)
+		}
+	}
+	heap.Init(&h)
+
+	var cur hashKey
+	var total uint64
+	started := false
+	for h.Len() > 0 {
+		rr := h[0]
+		if started && rr.key != cur {
+			if err := emit(cur, total); err != nil {
+				return err
+			}
+			total = 0
+		}
+		cur, started = rr.key, true
+		total += rr.n
+		ok, err := rr.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	if started {
+		return emit(cur, total)
+	}
+	return nil
+}
+
+// hashLines is the first pass over one input: it counts the hash of every line.
+func hashLines(f io.Reader, c *hashCounter, lh *lineHasher, n *normalizer) error {
+	input := bufio.NewScanner(f)
+	input.Buffer(nil, maxLineSize)
+	exact := n.identity()
+	for input.Scan() {
+		var k hashKey
+		if exact {
+			k = lh.sumBytes(input.Bytes())
+		} else {
+			k = lh.sum(n.key(input.Text()))
+		}
+		if err := c.add(k); err != nil {
+			return err
+		}
+	}
+	return input.Err()
+}
+
+// hashOptions configures the hashing mode.
+type hashOptions struct {
+	bits   int    // 64 or 128
+	mem    int64  // memory budget for the first pass, in bytes
+	dir    string // where run files and the copy of standard input go
+	binary bool   // read files that look binary too
+	stdin  io.Reader
+}
+
+// hashStats describes how the first pass went.
+type hashStats struct {
+	maxHeld int // the most hashes held in memory at once
+	runs    int // the number of run files written by the first pass
+	passes  int // the number of times the inputs were read again
+}
+
+// dupHashed runs both passes over 'files' (standard input if there are none) and
+// returns an index holding only the duplicated lines. Inputs that cannot be opened
+// are reported to 'errw' and skipped by both passes, as are binary files unless
+// 'opts.binary' is set. The passes read the files one after the other.
+func dupHashed(files []string, n *normalizer, opts hashOptions, errw io.Writer) (*index, *hashStats, error) {
+	lh, err := newLineHasher(opts.bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := newHashCounter(int(opts.mem/bytesPerKey), opts.dir)
+	defer c.cleanup()
+
+	// Standard input is spooled to a temporary file while it is hashed.
+	type input struct{ name, path string }
+	var inputs []input
+	if len(files) == 0 {
+		spool, err := os.CreateTemp(opts.dir, "dup-stdin-*")
+		if err != nil {
+			return nil, nil, err
+		}
+		defer os.Remove(spool.Name())
+		err = hashLines(io.TeeReader(opts.stdin, spool), c, lh, n)
+		if cerr := spool.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		inputs = append(inputs, input{"stdin", spool.Name()})
+	}
+	for _, arg := range files {
+		r, f, isBinary, err := openText(arg)
+		if err != nil {
+			fmt.Fprintf(errw, "dup2: %v\n", err)
+			continue
+		}
+		if isBinary && !opts.binary {
+			f.Close()
+			continue
+		}
+		err = hashLines(r, c, lh, n)
+		f.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", arg, err)
+		}
+		inputs = append(inputs, input{arg, arg})
+	}
+
+	// Every set of duplicated hashes takes one more pass over the inputs, which adds
+	// the lines of those hashes to the same index.
+	idx := newIndex(n)
+	for _, in := range inputs {
+		idx.addFile(in.name)
+	}
+	stats := &hashStats{}
+	err = c.duplicates(func(dups map[hashKey]bool) error {
+		stats.passes++
+		idx.keep = func(key string) bool { return dups[lh.sum(key)] }
+		for file, in := range inputs {
+			f, err := os.Open(in.path)
+			if err != nil {
+				return err
+			}
+			err = scanLines(f, idx, file)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %v", in.name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	stats.maxHeld, stats.runs = c.maxHeld, c.spilled
+	idx.keep = nil
+	return idx, stats, nil
+}
@@ -0,0 +1,226 @@
+// The hashing mode must produce exactly the report of the in-memory index, however
+// small its memory budget. The benchmarks generate synthetic files of growing size
+// and compare both modes. Run them with 'go test -run ^$ -bench . -benchmem'. Two
+// metrics show the memory bound: 'keys' is the most distinct entries held in memory
+// at once and 'peak-MB' the highest heap size seen while the benchmark ran. Both grow
+// with the input for the index but stay flat for '-hash'. 'B/op' is the total
+// allocated over the run, not what is live at once.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t testing.TB, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHashedMatchesIndex(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.txt", "x\ny\nX\nz\nx\nunique1\n")
+	b := writeFile(t, dir, "b.txt", "y\nw\nz\nunique2\n")
+	n := &normalizer{fold: true}
+
+	idx := newIndex(n)
+	for _, path := range []string{a, b} {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		countLines(f, idx, path)
+		f.Close()
+	}
+	var want bytes.Buffer
+	report(&want, idx.duplicates(false))
+
+	for _, bits := range []int{64, 128} {
+		// A budget of three entries forces several spills on this input.
+		opts := hashOptions{bits: bits, mem: 3 * bytesPerKey, dir: dir}
+		hidx, stats, err := dupHashed([]string{a, b}, n, opts, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.runs < 2 || stats.maxHeld > 3 {
+			t.Errorf("hash %d: %d runs, %d keys held; expected spills within 3 keys", bits, stats.runs, stats.maxHeld)
+		}
+		var got bytes.Buffer
+		report(&got, hidx.duplicates(false))
+		if got.String() != want.String() {
+			t.Errorf("hash %d report:\n%s\nexpected:\n%s", bits, got.String(), want.String())
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+// TestHashedManyRuns uses a budget so small that there are more runs than are merged
+// at once and more duplicated hashes than are held at once.
+func TestHashedManyRuns(t *testing.T) {
+	dir := t.TempDir()
+	var a, b strings.Builder
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&a, "line %d\n", i)
+		fmt.Fprintf(&b, "line %d\n", 299-i)
+		if i%3 == 0 {
+			fmt.Fprintf(&a, "line %d\n", i)
+		}
+	}
+	paths := []string{writeFile(t, dir, "a.txt", a.String()), writeFile(t, dir, "b.txt", b.String())}
+
+	idx := newIndex(nil)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		countLines(f, idx, path)
+		f.Close()
+	}
+	var want bytes.Buffer
+	report(&want, idx.duplicates(false))
+
+	opts := hashOptions{bits: 64, mem: 4 * bytesPerKey, dir: dir}
+	hidx, stats, err := dupHashed(paths, nil, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.runs <= mergeFanIn || stats.passes < 2 || stats.maxHeld > 4 {
+		t.Errorf("%d runs, %d passes, %d keys held; expected more than %d runs and several passes within 4 keys",
+			stats.runs, stats.passes, stats.maxHeld, mergeFanIn)
+	}
+	var got bytes.Buffer
+	report(&got, hidx.duplicates(false))
+	if got.String() != want.String() {
+		t.Errorf("report:\n%s\nexpected:\n%s", got.String(), want.String())
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestHashedStdin(t *testing.T) {
+	opts := hashOptions{bits: 64, mem: 1 << 20, dir: t.TempDir(), stdin: strings.NewReader("a\nb\na\n")}
+	idx, _, err := dupHashed(nil, nil, opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	report(&got, idx.duplicates(false))
+	if want := "2\ta\n\tstdin\t2\t1,3\n"; got.String() != want {
+		t.Errorf("report = %q, expected %q", got.String(), want)
+	}
+}
+
+// syntheticLog writes 'lines' log-like lines, of which about one in a hundred is a
+// repeat of an earlier one.
+func syntheticLog(b *testing.B, dir string, lines int) string {
+	path := filepath.Join(dir, fmt.Sprintf("synthetic-%d.log", lines))
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w := bufio.NewWriter(f)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < lines; i++ {
+		id := i
+		if i > 0 && rng.Intn(100) == 0 {
+			id = rng.Intn(i)
+		}
+		fmt.Fprintf(w, "request %08d served from cache node %d in %dms\n", id, id%17, id%997)
+	}
+	w.Flush()
+	f.Close()
+	return path
+}
+
+// heapPeak samples the heap size until 'stop' is called, which returns the largest
+// size seen above the heap size at the start, in MB.
+func heapPeak() (stop func() float64) {
+	var ms runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&ms)
+	base, peak := ms.HeapAlloc, ms.HeapAlloc
+	done := make(chan struct{})
+	result := make(chan uint64)
+	go func() {
+		var ms runtime.MemStats
+		tick := time.NewTicker(2 * time.Millisecond)
+		defer tick.Stop()
+		for {
+			select {
+			case <-done:
+				result <- peak
+				return
+			case <-tick.C:
+				runtime.ReadMemStats(&ms)
+				if ms.HeapAlloc > peak {
+					peak = ms.HeapAlloc
+				}
+			}
+		}
+	}()
+	return func() float64 {
+		close(done)
+		return float64(<-result-base) / (1 << 20)
+	}
+}
+
+func BenchmarkDup(b *testing.B) {
+	dir := b.TempDir()
+	const budget = 1 << 20 // 16384 keys
+	for _, lines := range []int{10000, 100000, 1000000} {
+		path := syntheticLog(b, dir, lines)
+
+		b.Run(fmt.Sprintf("lines=%d/mode=index", lines), func(b *testing.B) {
+			b.ReportAllocs()
+			stop := heapPeak()
+			for i := 0; i < b.N; i++ {
+				idx := newIndex(nil)
+				f, _ := os.Open(path)
+				countLines(f, idx, path)
+				f.Close()
+				idx.duplicates(false)
+				b.ReportMetric(float64(len(idx.lines)), "keys")
+			}
+			b.ReportMetric(stop(), "peak-MB")
+		})
+
+		b.Run(fmt.Sprintf("lines=%d/mode=hash64", lines), func(b *testing.B) {
+			b.ReportAllocs()
+			stop := heapPeak()
+			for i := 0; i < b.N; i++ {
+				opts := hashOptions{bits: 64, mem: budget, dir: dir}
+				idx, stats, err := dupHashed([]string{path}, nil, opts, io.Discard)
+				if err != nil {
+					b.Fatal(err)
+				}
+				idx.duplicates(false)
+				b.ReportMetric(float64(stats.maxHeld), "keys")
+				b.ReportMetric(float64(stats.runs), "runs")
+				b.ReportMetric(float64(stats.passes), "passes")
+			}
+			b.ReportMetric(stop(), "peak-MB")
+		})
+	}
+}
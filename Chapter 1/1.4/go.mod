@@ -0,0 +1,5 @@
+module GoBookSolutions/1.4
+
+go 1.20
+
+require golang.org/x/text v0.14.0
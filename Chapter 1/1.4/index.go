@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// occurrence is one place a line was seen: the position of its file in the order the
+// files were scanned, and the 1-based line number within that file.
+type occurrence struct {
+	file, line int
+}
+
+// less orders occurrences by file and then by line.
+func (o occurrence) less(p occurrence) bool {
+	if o.file != p.file {
+		return o.file < p.file
+	}
+	return o.line < p.line
+}
+
+// index maps every line to all of its occurrences. Unlike the old
+// 'map[string][]string' it keeps line numbers, and a line seen three times in one
+// file is recorded as three occurrences in that file rather than as three files.
+// Lines are grouped by their normalized key; see 'normalizer'.
+type index struct {
+	norm  *normalizer
+	files []string
+	lines map[string]*entry
+
+	// keep, if set, decides which keys are recorded at all. The hashing mode uses
+	// it on its second pass to only index lines already known to be duplicated.
+	keep func(key string) bool
+
+	// In block mode (see clone.go) the map of occurrences is not filled. Instead
+	// every key gets a number in 'ids', and 'seqs' holds the numbers of the lines of
+	// each file in order.
+	ids  map[string]uint32
+	seqs [][]uint32
+}
+
+// entry holds the occurrences of one key. 'text' is the line as it was written at
+// its first occurrence, which is what the report shows.
+type entry struct {
+	text  string
+	first occurrence
+	occs  []occurrence
+}
+
+func newIndex(n *normalizer) *index {
+	return &index{norm: n, lines: make(map[string]*entry)}
+}
+
+// newBlockIndex returns an index that records line sequences for 'findClones'.
+func newBlockIndex(n *normalizer) *index {
+	idx := newIndex(n)
+	idx.ids = make(map[string]uint32)
+	return idx
+}
+
+// addFile registers a file name and returns the number used for it in occurrences.
+func (idx *index) addFile(name string) int {
+	idx.files = append(idx.files, name)
+	if idx.ids != nil {
+		idx.seqs = append(idx.seqs, nil)
+	}
+	return len(idx.files) - 1
+}
+
+func (idx *index) add(file, line int, text string) {
+	o := occurrence{file, line}
+	key := idx.norm.key(text)
+	if idx.keep != nil && !idx.keep(key) {
+		return
+	}
+	if idx.ids != nil {
+		id, ok := idx.ids[key]
+		if !ok {
+			id = uint32(len(idx.ids))
+			idx.ids[key] = id
+		}
+		idx.seqs[file] = append(idx.seqs[file], id)
+		return
+	}
+	e := idx.lines[key]
+	if e == nil {
+		e = &entry{text: text, first: o}
+		idx.lines[key] = e
+	} else if o.less(e.first) {
+		e.text, e.first = text, o
+	}
+	e.occs = append(e.occs, o)
+}
+
+// merge adds the lines of 'local', an index of a single file, to 'idx' as file
+// number 'file'. Keys keep their first text as 'add' does, and in block mode the
+// key numbers of 'local' are translated to those of 'idx'.
+func (idx *index) merge(local *index, file int) {
+	for key, le := range local.lines {
+		occs := make([]occurrence, len(le.occs))
+		for i, o := range le.occs {
+			occs[i] = occurrence{file, o.line}
+		}
+		first := occurrence{file, le.first.line}
+		e := idx.lines[key]
+		if e == nil {
+			idx.lines[key] = &entry{text: le.text, first: first, occs: occs}
+			continue
+		}
+		if first.less(e.first) {
+			e.text, e.first = le.text, first
+		}
+		e.occs = append(e.occs, occs...)
+	}
+	if idx.ids == nil || len(local.seqs) == 0 {
+		return
+	}
+	remap := make([]uint32, len(local.ids))
+	for key, lid := range local.ids {
+		id, ok := idx.ids[key]
+		if !ok {
+			id = uint32(len(idx.ids))
+			idx.ids[key] = id
+		}
+		remap[lid] = id
+	}
+	seq := make([]uint32, len(local.seqs[0]))
+	for i, lid := range local.seqs[0] {
+		seq[i] = remap[lid]
+	}
+	idx.seqs[file] = seq
+}
+
+// duplicate is a line that occurs more than once, with its occurrences grouped by
+// file. 'files' follows the scan order and each file's line numbers are ascending.
+// When normalization is on, 'text' is the first of the matching lines.
+type duplicate struct {
+	text  string
+	count int
+	files []fileLines
+}
+
+type fileLines struct {
+	name  string
+	lines []int
+}
+
+// duplicates returns every line seen more than once. With 'cross' set, only lines
+// found in at least two different files are returned. The result is sorted by count,
+// highest first, and then by text, so the report no longer depends on map order.
+func (idx *index) duplicates(cross bool) []duplicate {
+	var dups []duplicate
+	for _, e := range idx.lines {
+		occs := e.occs
+		if len(occs) < 2 {
+			continue
+		}
+		sort.Slice(occs, func(i, j int) bool { return occs[i].less(occs[j]) })
+		d := duplicate{text: e.text, count: len(occs)}
+		// Occurrences are grouped by the number of their file, not its name, so
+		// that a file read twice still gets ascending line numbers in each group.
+		file := -1
+		for _, o := range occs {
+			if o.file != file {
+				file = o.file
+				d.files = append(d.files, fileLines{name: idx.files[file]})
+			}
+			last := &d.files[len(d.files)-1]
+			last.lines = append(last.lines, o.line)
+		}
+		if cross && len(d.files) < 2 {
+			continue
+		}
+		dups = append(dups, d)
+	}
+	sort.Slice(dups, func(i, j int) bool {
+		if dups[i].count != dups[j].count {
+			return dups[i].count > dups[j].count
+		}
+		return dups[i].text < dups[j].text
+	})
+	return dups
+}
+
+// report prints each duplicate line with its total count, followed by one line per
+// file giving how many times it appears there and on which lines:
+//
+//	3	hello
+//		a.txt	2	1,4
+//		b.txt	1	7
+func report(w io.Writer, dups []duplicate) {
+	for _, d := range dups {
+		fmt.Fprintf(w, "%d\t%s\n", d.count, d.text)
+		for _, f := range d.files {
+			nums := make([]string, len(f.lines))
+			for i, n := range f.lines {
+				nums[i] = fmt.Sprint(n)
+			}
+			fmt.Fprintf(w, "\t%s\t%d\t%s\n", f.name, len(f.lines), strings.Join(nums, ","))
+		}
+	}
+}
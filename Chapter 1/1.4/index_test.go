@@ -0,0 +1,73 @@
+// These tests feed small inputs through 'countLines' and check that occurrences are
+// counted per file with their line numbers, and that the report does not depend on
+// map iteration order. Run them with 'go test'.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func scan(t *testing.T, idx *index, inputs ...string) {
+	t.Helper()
+	for i := 0; i < len(inputs); i += 2 {
+		if err := countLines(strings.NewReader(inputs[i+1]), idx, inputs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	idx := newIndex(nil)
+	scan(t, idx,
+		"a.txt", "x\ny\nx\nz\nx\n",
+		"b.txt", "y\nw\nz\n",
+	)
+
+	var buf bytes.Buffer
+	report(&buf, idx.duplicates(false))
+	want := "3\tx\n" +
+		"\ta.txt\t3\t1,3,5\n" +
+		"2\ty\n" +
+		"\ta.txt\t1\t2\n" +
+		"\tb.txt\t1\t1\n" +
+		"2\tz\n" +
+		"\ta.txt\t1\t4\n" +
+		"\tb.txt\t1\t3\n"
+	if buf.String() != want {
+		t.Errorf("report:\n%s\nexpected:\n%s", buf.String(), want)
+	}
+}
+
+// TestSameNameTwice checks that two inputs with the same name are kept apart, each
+// with its lines in order, rather than merged into one unordered list.
+func TestSameNameTwice(t *testing.T) {
+	idx := newIndex(nil)
+	scan(t, idx, "a.txt", "x\ny\nx\n", "a.txt", "x\ny\nx\n")
+
+	var buf bytes.Buffer
+	report(&buf, idx.duplicates(false))
+	want := "4\tx\n" +
+		"\ta.txt\t2\t1,3\n" +
+		"\ta.txt\t2\t1,3\n" +
+		"2\ty\n" +
+		"\ta.txt\t1\t2\n" +
+		"\ta.txt\t1\t2\n"
+	if buf.String() != want {
+		t.Errorf("report:\n%s\nexpected:\n%s", buf.String(), want)
+	}
+}
+
+func TestCross(t *testing.T) {
+	idx := newIndex(nil)
+	scan(t, idx,
+		"a.txt", "x\nx\nx\ny\n",
+		"b.txt", "y\n",
+	)
+	dups := idx.duplicates(true)
+	if len(dups) != 1 || dups[0].text != "y" {
+		t.Fatalf("duplicates(cross) = %+v, expected only y", dups)
+	}
+}
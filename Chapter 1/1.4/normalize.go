@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizer turns a line into the key it is compared by. The zero value compares
+// lines exactly, as 'input.Text()' was compared before. The steps are applied in a
+// fixed order, whatever order the flags were given in:
+//
+//  1. Unicode normalization (NFC or NFKC), so that "é" and "é" match;
+//  2. every match of every '-strip' regular expression is removed, e.g. timestamps;
+//  3. leading indentation is dropped, or surrounding whitespace trimmed, or runs
+//     of whitespace collapsed to a single space (which also trims);
+//  4. Unicode case folding.
+type normalizer struct {
+	form     string // "", "nfc" or "nfkc"
+	strip    regexpList
+	noIndent bool
+	trim     bool
+	collapse bool
+	fold     bool
+}
+
+// identity reports whether every line is its own key, in which case we can skip
+// the work altogether.
+func (n *normalizer) identity() bool {
+	return n == nil || n.form == "" && len(n.strip) == 0 && !n.noIndent && !n.trim && !n.collapse && !n.fold
+}
+
+func (n *normalizer) key(s string) string {
+	if n.identity() {
+		return s
+	}
+	switch n.form {
+	case "nfc":
+		s = norm.NFC.String(s)
+	case "nfkc":
+		s = norm.NFKC.String(s)
+	}
+	for _, re := range n.strip {
+		s = re.ReplaceAllString(s, "")
+	}
+	if n.noIndent {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	}
+	if n.trim {
+		s = strings.TrimSpace(s)
+	}
+	if n.collapse {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	if n.fold {
+		s = cases.Fold().String(s)
+	}
+	return s
+}
+
+// setForm validates the value of the '-norm' flag.
+func (n *normalizer) setForm(form string) error {
+	switch f := strings.ToLower(form); f {
+	case "", "none":
+		n.form = ""
+	case "nfc", "nfkc":
+		n.form = f
+	default:
+		return fmt.Errorf("unknown normalization form %q (want nfc or nfkc)", form)
+	}
+	return nil
+}
+
+// regexpList is a 'flag.Value' collecting every '-strip' expression given.
+type regexpList []*regexp.Regexp
+
+func (l *regexpList) String() string {
+	if l == nil {
+		return ""
+	}
+	exprs := make([]string, len(*l))
+	for i, re := range *l {
+		exprs[i] = re.String()
+	}
+	return strings.Join(exprs, " ")
+}
+
+func (l *regexpList) Set(expr string) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, re)
+	return nil
+}
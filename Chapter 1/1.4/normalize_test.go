@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestNormalizerKey(t *testing.T) {
+	timestamp := new(regexpList)
+	if err := timestamp.Set(`^\d{4}-\d\d-\d\dT[0-9:.]+Z? `); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		n    normalizer
+		a, b string
+		same bool
+	}{
+		{normalizer{}, "Hello", "hello", false},
+		{normalizer{fold: true}, "Straße", "STRASSE", true},
+		{normalizer{trim: true}, "  x = 1 ", "x = 1", true},
+		{normalizer{trim: true}, "x  = 1", "x = 1", false},
+		{normalizer{collapse: true}, " x \t =  1", "x = 1", true},
+		{normalizer{noIndent: true}, "\t\treturn nil", "    return nil", true},
+		{normalizer{noIndent: true}, "return nil ", "return nil", false},
+		{normalizer{}, "é", "é", false},
+		{normalizer{form: "nfc"}, "é", "é", true},
+		{normalizer{form: "nfc"}, "ﬁle", "file", false},
+		{normalizer{form: "nfkc"}, "ﬁle", "file", true},
+		{normalizer{strip: *timestamp}, "2024-01-02T10:00:00Z disk full", "2024-03-04T11:22:33.5Z disk full", true},
+		{normalizer{strip: *timestamp, fold: true, collapse: true}, "2024-01-02T10:00:00Z Disk   FULL", "2024-03-04T11:22:33Z disk full ", true},
+	}
+	for _, test := range tests {
+		ka, kb := test.n.key(test.a), test.n.key(test.b)
+		if (ka == kb) != test.same {
+			t.Errorf("%+v: key(%q) = %q, key(%q) = %q, expected same = %t", test.n, test.a, ka, test.b, kb, test.same)
+		}
+	}
+}
+
+func TestNormalizedReportShowsFirstText(t *testing.T) {
+	idx := newIndex(&normalizer{fold: true})
+	scan(t, idx, "a.txt", "second\nHELLO\n", "b.txt", "hello\n")
+	dups := idx.duplicates(false)
+	if len(dups) != 1 || dups[0].text != "HELLO" || dups[0].count != 2 {
+		t.Errorf("duplicates = %+v, expected HELLO twice", dups)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// scanFiles reads 'files' with a pool of 'workers' goroutines. File numbers are
+// assigned up front in the order of 'files', each worker runs the usual
+// 'countLines' loop into an index of its own, and the result is merged into 'idx'
+// under a mutex. Since occurrences are sorted when reported and the text shown for
+// a line is that of its earliest occurrence, the output does not depend on which
+// worker finishes first. Errors are written to 'errw' in file order at the end.
+// Files that look binary are skipped unless 'binary' is set.
+func scanFiles(files []string, idx *index, workers int, binary bool, errw io.Writer) {
+	if workers < 1 {
+		workers = 1
+	}
+	base := len(idx.files)
+	for _, name := range files {
+		idx.addFile(name)
+	}
+
+	errs := make([]string, len(files))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				name := files[i]
+				r, f, isBinary, err := openText(name)
+				if err != nil {
+					errs[i] = fmt.Sprintf("dup2: %v", err)
+					continue
+				}
+				if isBinary && !binary {
+					f.Close()
+					continue
+				}
+				local := newIndex(idx.norm)
+				if idx.ids != nil {
+					local = newBlockIndex(idx.norm)
+				}
+				err = countLines(r, local, name)
+				f.Close()
+				if err != nil {
+					errs[i] = fmt.Sprintf("dup2: %s: %v", name, err)
+				}
+				mu.Lock()
+				idx.merge(local, base+i)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, e := range errs {
+		if e != "" {
+			fmt.Fprintln(errw, e)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/* With '-r', directories given on the command line are walked recursively. Files are
+visited in lexical order, which together with the fixed file numbering in
+'scanFiles' keeps the output the same however the reads are scheduled.
+
+Walked files are filtered by glob patterns. A pattern without a slash is matched
+against the base name ('*.go'); a pattern with one is matched against the path
+relative to the directory given on the command line ('cmd/*.go', 'internal/**').
+'*' and '?' do not cross a '/', while '**' matches any number of directories.
+'-exclude' also prunes whole directories. Files named explicitly on the command line
+are always read.
+
+With '-gitignore', the '.gitignore' file of every walked directory is honoured, with
+the usual rules: '#' comments, '!' negation, a trailing '/' for directories only, and
+patterns containing a '/' anchored to the directory of their '.gitignore'. The '.git'
+directory itself is always skipped in that mode. */
+
+// globList is a 'flag.Value' collecting repeated glob patterns.
+type globList []*glob
+
+func (l *globList) String() string {
+	if l == nil {
+		return ""
+	}
+	pats := make([]string, len(*l))
+	for i, g := range *l {
+		pats[i] = g.pattern
+	}
+	return strings.Join(pats, " ")
+}
+
+func (l *globList) Set(pattern string) error {
+	g, err := compileGlob(pattern)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, g)
+	return nil
+}
+
+// match reports whether any pattern matches the slash-separated relative path.
+func (l globList) match(rel string) bool {
+	for _, g := range l {
+		if g.match(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// glob is a compiled glob pattern.
+type glob struct {
+	pattern  string
+	anchored bool // matched against the whole relative path, not the base name
+	re       *regexp.Regexp
+}
+
+func compileGlob(pattern string) (*glob, error) {
+	p := strings.TrimPrefix(pattern, "/")
+	anchored := p != pattern || strings.Contains(p, "/")
+	re, err := globRegexp(p)
+	if err != nil {
+		return nil, fmt.Errorf("bad pattern %q: %v", pattern, err)
+	}
+	return &glob{pattern: pattern, anchored: anchored, re: re}, nil
+}
+
+func (g *glob) match(rel string) bool {
+	if g.anchored {
+		return g.re.MatchString(rel)
+	}
+	return g.re.MatchString(path.Base(rel))
+}
+
+// globRegexp translates a glob into an anchored regular expression.
+func globRegexp(p string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(p[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := p[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(p) {
+				i++
+				b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// ignoreRule is one line of a '.gitignore' file found in directory 'dir' (a path
+// relative to the walk root, "" for the root itself).
+type ignoreRule struct {
+	dir     string
+	g       *glob
+	negate  bool
+	dirOnly bool
+}
+
+// readGitignore parses the '.gitignore' in 'abs', if there is one.
+func readGitignore(abs, dir string) ([]ignoreRule, error) {
+	data, err := os.ReadFile(filepath.Join(abs, ".gitignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var rules []ignoreRule
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimRight(line, " ")
+		r := ignoreRule{dir: dir}
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		g, err := compileGlob(line)
+		if err != nil {
+			continue // git ignores patterns it cannot parse, so do we
+		}
+		r.g = g
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// ignored applies the rules in order, the last matching one winning, as git does.
+func ignored(rules []ignoreRule, rel string, isDir bool) bool {
+	ignore := false
+	for _, r := range rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		sub := rel
+		if r.dir != "" {
+			if !strings.HasPrefix(rel, r.dir+"/") {
+				continue
+			}
+			sub = rel[len(r.dir)+1:]
+		}
+		if r.g.match(sub) {
+			ignore = !r.negate
+		}
+	}
+	return ignore
+}
+
+// walkOptions configures 'collectFiles'.
+type walkOptions struct {
+	recursive bool
+	include   globList
+	exclude   globList
+	gitignore bool
+}
+
+// collectFiles expands the command line arguments into the list of files to read.
+// Problems are reported to 'errw' and the offending argument skipped. A file named
+// twice, as in 'dup2 a.txt ./a.txt' or by a file and a directory holding it, is
+// read once, where it is first named; otherwise its lines would be counted twice.
+func collectFiles(args []string, opts walkOptions, errw io.Writer) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if clean := filepath.Clean(name); !seen[clean] {
+			seen[clean] = true
+			files = append(files, name)
+		}
+	}
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			fmt.Fprintf(errw, "dup2: %v\n", err)
+			continue
+		}
+		if !info.IsDir() {
+			add(arg)
+			continue
+		}
+		if !opts.recursive {
+			fmt.Fprintf(errw, "dup2: %s is a directory (use -r to walk it)\n", arg)
+			continue
+		}
+		for _, name := range walkDir(arg, opts, errw) {
+			add(name)
+		}
+	}
+	return files
+}
+
+func walkDir(root string, opts walkOptions, errw io.Writer) []string {
+	var files []string
+	var rules []ignoreRule
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Fprintf(errw, "dup2: %v\n", err)
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel == "." {
+				rel = ""
+			} else if opts.exclude.match(rel) ||
+				opts.gitignore && (d.Name() == ".git" || ignored(rules, rel, true)) {
+				return filepath.SkipDir
+			}
+			if opts.gitignore {
+				more, err := readGitignore(p, rel)
+				if err != nil {
+					fmt.Fprintf(errw, "dup2: %v\n", err)
+				}
+				rules = append(rules, more...)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if len(opts.include) > 0 && !opts.include.match(rel) {
+			return nil
+		}
+		if opts.exclude.match(rel) || opts.gitignore && ignored(rules, rel, false) {
+			return nil
+		}
+		files = append(files, p)
+		return nil
+	})
+	return files
+}
+
+// sniffLen is how much of a file is checked for NUL bytes, the same amount git uses.
+const sniffLen = 8000
+
+// openText opens a file for reading and reports whether it looks binary, i.e. has
+// a NUL byte in its first 'sniffLen' bytes. The returned reader still yields the
+// whole file.
+func openText(name string) (r io.Reader, f *os.File, binary bool, err error) {
+	f, err = os.Open(name)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	br := bufio.NewReaderSize(f, sniffLen)
+	head, _ := br.Peek(sniffLen)
+	return br, f, bytes.IndexByte(head, 0) >= 0, nil
+}
@@ -0,0 +1,201 @@
+// These tests build a small directory tree in a temporary directory and check which
+// files the walk picks under globs and '.gitignore' rules, that binary files are
+// skipped, and that the report is the same whatever the number of workers. Run them
+// with 'go test'.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// relFiles returns the collected files relative to 'root', with slashes.
+func relFiles(t *testing.T, root string, files []string) []string {
+	t.Helper()
+	var rel []string
+	for _, f := range files {
+		r, err := filepath.Rel(root, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	return rel
+}
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", true},
+		{"*.go", "main.go.txt", false},
+		{"cmd/*.go", "cmd/main.go", true},
+		{"cmd/*.go", "cmd/sub/main.go", false},
+		{"cmd/**", "cmd/sub/main.go", true},
+		{"**/testdata/*", "a/b/testdata/x", true},
+		{"**/testdata/*", "testdata/x", true},
+		{"/build", "build", true},
+		{"/build", "src/build", false},
+		{"file?.txt", "file1.txt", true},
+		{"file[!0-9].txt", "file1.txt", false},
+		{"file[!0-9].txt", "fileA.txt", true},
+		{`\*.txt`, "*.txt", true},
+		{`\*.txt`, "a.txt", false},
+	}
+	for _, test := range tests {
+		g, err := compileGlob(test.pattern)
+		if err != nil {
+			t.Fatalf("compileGlob(%q): %v", test.pattern, err)
+		}
+		if got := g.match(test.path); got != test.want {
+			t.Errorf("%q matches %q = %v, expected %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
+
+func TestCollectFiles(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		".gitignore":        "*.log\nbuild/\n!keep.log\n",
+		"a.go":              "a\n",
+		"a.txt":             "a\n",
+		"keep.log":          "k\n",
+		"debug.log":         "d\n",
+		"build/out.go":      "o\n",
+		"cmd/main.go":       "m\n",
+		"cmd/.gitignore":    "/gen.go\n",
+		"cmd/gen.go":        "g\n",
+		"cmd/sub/gen.go":    "g\n",
+		"vendor/lib/lib.go": "l\n",
+		".git/HEAD":         "ref\n",
+	})
+
+	tests := []struct {
+		name string
+		opts walkOptions
+		want []string
+	}{
+		{"all", walkOptions{recursive: true}, []string{
+			".git/HEAD", ".gitignore", "a.go", "a.txt", "build/out.go", "cmd/.gitignore",
+			"cmd/gen.go", "cmd/main.go", "cmd/sub/gen.go", "debug.log", "keep.log",
+			"vendor/lib/lib.go",
+		}},
+		{"gitignore", walkOptions{recursive: true, gitignore: true}, []string{
+			".gitignore", "a.go", "a.txt", "cmd/.gitignore", "cmd/main.go",
+			"cmd/sub/gen.go", "keep.log", "vendor/lib/lib.go",
+		}},
+		{"include", walkOptions{recursive: true, gitignore: true, include: mustGlobs(t, "*.go")}, []string{
+			"a.go", "cmd/main.go", "cmd/sub/gen.go", "vendor/lib/lib.go",
+		}},
+		{"exclude", walkOptions{recursive: true, gitignore: true,
+			include: mustGlobs(t, "*.go"), exclude: mustGlobs(t, "vendor", "cmd/sub/**")}, []string{
+			"a.go", "cmd/main.go",
+		}},
+	}
+	for _, test := range tests {
+		var errs bytes.Buffer
+		got := relFiles(t, root, collectFiles([]string{root}, test.opts, &errs))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: collected %q, expected %q", test.name, got, test.want)
+		}
+		if errs.Len() > 0 {
+			t.Errorf("%s: unexpected errors: %s", test.name, errs.String())
+		}
+	}
+
+	var errs bytes.Buffer
+	if files := collectFiles([]string{root}, walkOptions{}, &errs); len(files) != 0 || errs.Len() == 0 {
+		t.Errorf("directory without -r: got %q and errors %q, expected an error only", files, errs.String())
+	}
+
+	// A file named twice, or named and found again in a directory, is read once.
+	a := filepath.Join(root, "a.txt")
+	args := []string{a, filepath.Join(root, ".", "a.txt"), root}
+	got := relFiles(t, root, collectFiles(args, walkOptions{recursive: true, include: mustGlobs(t, "*.txt")}, &errs))
+	if want := []string{"a.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("collected %q from %q, expected %q", got, args, want)
+	}
+}
+
+func mustGlobs(t *testing.T, patterns ...string) globList {
+	t.Helper()
+	var l globList
+	for _, p := range patterns {
+		if err := l.Set(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestScanFilesDeterministic(t *testing.T) {
+	files := map[string]string{
+		"bin.dat": "x\n\x00\nx\n",
+	}
+	for i := 0; i < 20; i++ {
+		name := filepath.Join("d", string(rune('a'+i))+".txt")
+		files[name] = "common\nX\nunique " + name + "\nx\n"
+	}
+	root := writeTree(t, files)
+	list := collectFiles([]string{root}, walkOptions{recursive: true}, os.Stderr)
+
+	run := func(workers int, binary bool) string {
+		idx := newIndex(&normalizer{fold: true})
+		var errs bytes.Buffer
+		scanFiles(list, idx, workers, binary, &errs)
+		if errs.Len() > 0 {
+			t.Errorf("scanFiles: %s", errs.String())
+		}
+		var buf bytes.Buffer
+		report(&buf, idx.duplicates(false))
+		return buf.String()
+	}
+
+	// The text shown for a folded line is that of its earliest occurrence, 'X' on
+	// line 2 of d/a.txt, not that of whichever file a worker merged first.
+	want := run(1, false)
+	if !bytes.Contains([]byte(want), []byte("40\tX\n")) {
+		t.Errorf("expected 'X' to be reported 40 times:\n%s", want)
+	}
+	if bytes.Contains([]byte(want), []byte("bin.dat")) {
+		t.Errorf("binary file was read:\n%s", want)
+	}
+	for _, workers := range []int{2, 4, 16} {
+		for rep := 0; rep < 5; rep++ {
+			if got := run(workers, false); got != want {
+				t.Fatalf("%d workers:\n%s\nexpected:\n%s", workers, got, want)
+			}
+		}
+	}
+	if got := run(4, true); !bytes.Contains([]byte(got), []byte("bin.dat")) {
+		t.Errorf("binary file was skipped with binary set:\n%s", got)
+	}
+
+	idx := newBlockIndex(nil)
+	scanFiles(list, idx, 8, false, os.Stderr)
+	serial := newBlockIndex(nil)
+	scanFiles(list, serial, 1, false, os.Stderr)
+	if !reflect.DeepEqual(findClones(idx.seqs, 2), findClones(serial.seqs, 2)) {
+		t.Errorf("block mode clones depend on the number of workers")
+	}
+}
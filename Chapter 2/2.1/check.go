@@ -0,0 +1,191 @@
+package tempconv
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"GoBookSolutions/2.1/unit"
+)
+
+/* The conversion types accept any float64, so 'Kelvin(-5)' compiles and runs. The
+checked constructors and the parsers below reject what cannot be a temperature:
+values below absolute zero, and NaN or infinities. They return a '*TemperatureError'
+that wraps one of the sentinel errors, so callers can test for the reason with
+'errors.Is'.
+
+The parsers take a number with an optional unit, in any scale the 'unit' package
+knows: '20', '20C', '20 °C', '68F' or '293.15 K'. A bare number is in the scale
+being parsed; a number in another scale is converted. Parsing works on the decimal
+as written, with the exact conversions of exact.go, and rounds only the result, so
+'68F' is exactly 20°C and '-459.67F' exactly absolute zero, which a float64 check
+after a float64 conversion would get wrong by a rounding error.
+
+'*Celsius', '*Fahrenheit' and '*Kelvin' implement 'flag.Value' with these parsers,
+which lets a command take '-temp 20C' or '-temp 68F' directly. */
+
+var (
+	ErrBelowAbsoluteZero = errors.New("below absolute zero")
+	ErrNotFinite         = errors.New("not a finite number")
+	ErrSyntax            = errors.New("invalid syntax")
+)
+
+// TemperatureError describes a value that was rejected. 'Input' is the text that
+// was parsed, or the formatted value for the constructors.
+type TemperatureError struct {
+	Input string
+	Err   error
+}
+
+func (e *TemperatureError) Error() string {
+	return fmt.Sprintf("tempconv: %q: %v", e.Input, e.Err)
+}
+
+func (e *TemperatureError) Unwrap() error { return e.Err }
+
+func NewCelsius(v float64) (Celsius, error) {
+	err := check(strconv.FormatFloat(v, 'g', -1, 64), v, unit.Celsius)
+	return Celsius(v), err
+}
+
+func NewFahrenheit(v float64) (Fahrenheit, error) {
+	err := check(strconv.FormatFloat(v, 'g', -1, 64), v, unit.Fahrenheit)
+	return Fahrenheit(v), err
+}
+
+func NewKelvin(v float64) (Kelvin, error) {
+	err := check(strconv.FormatFloat(v, 'g', -1, 64), v, unit.Kelvin)
+	return Kelvin(v), err
+}
+
+// check validates 'v' in unit 'u'.
+func check(input string, v float64, u *unit.Unit) error {
+	switch {
+	case math.IsNaN(v) || math.IsInf(v, 0):
+		return &TemperatureError{input, ErrNotFinite}
+	case colder(compare(v, absoluteZero(u)), u):
+		return belowZero(input, u)
+	}
+	return nil
+}
+
+// absoluteZero is where a temperature unit starts: at minus its offset. Subtracting
+// from 0 rather than negating gives 0 and not -0 for the kelvin.
+func absoluteZero(u *unit.Unit) float64 { return 0 - u.Offset }
+
+// colder reports whether the result 'cmp' of comparing a value with another, -1, 0
+// or +1, means that the value is colder in unit 'u'. It usually means less, but not
+// on the Delisle scale, which counts down.
+func colder(cmp int, u *unit.Unit) bool {
+	if u.Num < 0 {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func compare(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func belowZero(input string, u *unit.Unit) error {
+	return &TemperatureError{input, fmt.Errorf("%w (%g%s)", ErrBelowAbsoluteZero, absoluteZero(u), u.Symbol)}
+}
+
+func ParseCelsius(s string) (Celsius, error) {
+	v, err := parse(s, unit.Celsius)
+	return Celsius(v), err
+}
+
+func ParseFahrenheit(s string) (Fahrenheit, error) {
+	v, err := parse(s, unit.Fahrenheit)
+	return Fahrenheit(v), err
+}
+
+func ParseKelvin(s string) (Kelvin, error) {
+	v, err := parse(s, unit.Kelvin)
+	return Kelvin(v), err
+}
+
+var numberPrefix = regexp.MustCompile(`^[+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?`)
+
+// parse reads a temperature and converts it to unit 'to'.
+func parse(s string, to *unit.Unit) (float64, error) {
+	text := strings.TrimSpace(s)
+	num := numberPrefix.FindString(text)
+	v, ok := new(big.Rat).SetString(num)
+	if num == "" || !ok {
+		return 0, &TemperatureError{s, ErrSyntax}
+	}
+	from, err := to, error(nil)
+	if sym := strings.TrimSpace(text[len(num):]); sym != "" {
+		if from, err = unit.Default.Parse(sym); err != nil {
+			return 0, &TemperatureError{s, err}
+		}
+		if from.Dim != unit.Temperature {
+			return 0, &TemperatureError{s, &unit.DimensionError{From: from, To: to}}
+		}
+	}
+	if colder(v.Cmp(unit.ExactFactor(absoluteZero(from))), from) {
+		return 0, belowZero(s, from)
+	}
+	// The check is exact, but the result may still be too large for a float64, as
+	// "1e400" is.
+	r, _ := convExact(v, from, to).Float64()
+	if math.IsInf(r, 0) {
+		return 0, &TemperatureError{s, ErrNotFinite}
+	}
+	return r, nil
+}
+
+func (c *Celsius) Set(s string) error {
+	v, err := ParseCelsius(s)
+	if err == nil {
+		*c = v
+	}
+	return err
+}
+
+func (f *Fahrenheit) Set(s string) error {
+	v, err := ParseFahrenheit(s)
+	if err == nil {
+		*f = v
+	}
+	return err
+}
+
+func (k *Kelvin) Set(s string) error {
+	v, err := ParseKelvin(s)
+	if err == nil {
+		*k = v
+	}
+	return err
+}
+
+// CelsiusFlag defines a Celsius flag with the given name, default value and usage
+// on the default flag set, and returns the address of the variable that holds it.
+// The Fahrenheit and Kelvin versions work the same way.
+func CelsiusFlag(name string, value Celsius, usage string) *Celsius {
+	flag.CommandLine.Var(&value, name, usage)
+	return &value
+}
+
+func FahrenheitFlag(name string, value Fahrenheit, usage string) *Fahrenheit {
+	flag.CommandLine.Var(&value, name, usage)
+	return &value
+}
+
+func KelvinFlag(name string, value Kelvin, usage string) *Kelvin {
+	flag.CommandLine.Var(&value, name, usage)
+	return &value
+}
@@ -0,0 +1,125 @@
+/* These tests cover the checked constructors, the parsers and the flag values:
+physically impossible temperatures must be rejected with an error that 'errors.Is'
+recognizes, and values in any scale must be converted to the scale being parsed.
+Run them with 'go test'. */
+
+package tempconv
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := NewKelvin(-5); !errors.Is(err, ErrBelowAbsoluteZero) {
+		t.Errorf("NewKelvin(-5) error = %v, expected ErrBelowAbsoluteZero", err)
+	}
+	if _, err := NewCelsius(-273.16); !errors.Is(err, ErrBelowAbsoluteZero) {
+		t.Errorf("NewCelsius(-273.16) error = %v, expected ErrBelowAbsoluteZero", err)
+	}
+	if _, err := NewFahrenheit(math.NaN()); !errors.Is(err, ErrNotFinite) {
+		t.Errorf("NewFahrenheit(NaN) error = %v, expected ErrNotFinite", err)
+	}
+	if _, err := NewCelsius(math.Inf(1)); !errors.Is(err, ErrNotFinite) {
+		t.Errorf("NewCelsius(+Inf) error = %v, expected ErrNotFinite", err)
+	}
+	for _, v := range []float64{float64(AbsoluteZeroK), 0, 300} {
+		if _, err := NewKelvin(v); err != nil {
+			t.Errorf("NewKelvin(%v): %v", v, err)
+		}
+	}
+	if _, err := NewCelsius(float64(AbsoluteZeroC)); err != nil {
+		t.Errorf("NewCelsius(AbsoluteZeroC): %v", err)
+	}
+	if _, err := NewFahrenheit(float64(AbsoluteZeroF)); err != nil {
+		t.Errorf("NewFahrenheit(AbsoluteZeroF): %v", err)
+	}
+
+	_, err := NewKelvin(-5)
+	var terr *TemperatureError
+	if !errors.As(err, &terr) || err.Error() != `tempconv: "-5": below absolute zero (0K)` {
+		t.Errorf("NewKelvin(-5) error = %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Celsius
+	}{
+		{"20", 20},
+		{"20C", 20},
+		{" 20 °C ", 20},
+		{"-40F", -40},
+		{"212 °F", 100},
+		{"273.15K", 0},
+		{"-273.15", AbsoluteZeroC},
+		{"-459.67F", AbsoluteZeroC},
+		{"0 K", AbsoluteZeroC},
+		{"1e2", 100},
+	}
+	for _, test := range tests {
+		got, err := ParseCelsius(test.input)
+		if err != nil || math.Abs(float64(got-test.want)) > 1e-12 {
+			t.Errorf("ParseCelsius(%q) = %v, %v; expected %v", test.input, got, err, test.want)
+		}
+	}
+	if k, err := ParseKelvin("-459.67 °F"); err != nil || k != 0 {
+		t.Errorf("ParseKelvin(-459.67 °F) = %v, %v; expected 0K", k, err)
+	}
+
+	errTests := []struct {
+		input string
+		err   error
+	}{
+		{"-5K", ErrBelowAbsoluteZero},
+		{"-300", ErrBelowAbsoluteZero},
+		{"-460 F", ErrBelowAbsoluteZero},
+		{"", ErrSyntax},
+		{"warm", ErrSyntax},
+		{"NaN", ErrSyntax},
+		{"1e400", ErrNotFinite},
+		{"1e400 K", ErrNotFinite},
+	}
+	for _, test := range errTests {
+		if _, err := ParseCelsius(test.input); !errors.Is(err, test.err) {
+			t.Errorf("ParseCelsius(%q) error = %v, expected %v", test.input, err, test.err)
+		}
+	}
+	if _, err := ParseFahrenheit("1e400"); !errors.Is(err, ErrNotFinite) {
+		t.Errorf("ParseFahrenheit(1e400) error = %v, expected ErrNotFinite", err)
+	}
+	if _, err := ParseKelvin("1e400C"); !errors.Is(err, ErrNotFinite) {
+		t.Errorf("ParseKelvin(1e400C) error = %v, expected ErrNotFinite", err)
+	}
+	if _, err := ParseCelsius("20 m"); err == nil || err.Error() != `tempconv: "20 m": cannot convert m (length) to °C (temperature)` {
+		t.Errorf("ParseCelsius(20 m) error = %v", err)
+	}
+	if _, err := ParseFahrenheit("20 X"); err == nil {
+		t.Errorf("ParseFahrenheit(20 X) succeeded")
+	}
+}
+
+func TestFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	c, f, k := Celsius(20), Fahrenheit(0), Kelvin(0)
+	fs.Var(&c, "temp", "temperature")
+	fs.Var(&f, "f", "temperature")
+	fs.Var(&k, "k", "temperature")
+	if err := fs.Parse([]string{"-temp", "68F", "-f", "100C", "-k", "-273.15C"}); err != nil {
+		t.Fatal(err)
+	}
+	if c != 20 || f != 212 || k != 0 {
+		t.Errorf("flags = %v, %v, %v; expected 20°C, 212°F, 0K", c, f, k)
+	}
+	if err := fs.Parse([]string{"-k", "-1K"}); err == nil {
+		t.Errorf("-k -1K was accepted")
+	}
+	if k != 0 {
+		t.Errorf("a rejected value changed the flag to %v", k)
+	}
+}
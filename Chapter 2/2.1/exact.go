@@ -0,0 +1,33 @@
+package tempconv
+
+import (
+	"math/big"
+
+	"GoBookSolutions/2.1/unit"
+)
+
+/* Exact versions of the six conversions, on 'big.Rat' values. 273.15 and 459.67 are
+taken as the decimals they are, and 5/9 as a fraction, so that 'CToKExact' of
+'KToCExact(k)' is exactly 'k' for every rational 'k', which float64 cannot
+promise. The results are new values; the arguments are not modified. */
+
+func CToFExact(c *big.Rat) *big.Rat { return convExact(c, unit.Celsius, unit.Fahrenheit) }
+
+func FToCExact(f *big.Rat) *big.Rat { return convExact(f, unit.Fahrenheit, unit.Celsius) }
+
+func KToCExact(k *big.Rat) *big.Rat { return convExact(k, unit.Kelvin, unit.Celsius) }
+
+func CToKExact(c *big.Rat) *big.Rat { return convExact(c, unit.Celsius, unit.Kelvin) }
+
+func KToFExact(k *big.Rat) *big.Rat { return convExact(k, unit.Kelvin, unit.Fahrenheit) }
+
+func FToKExact(f *big.Rat) *big.Rat { return convExact(f, unit.Fahrenheit, unit.Kelvin) }
+
+// convExact cannot fail, since all three units measure temperature.
+func convExact(v *big.Rat, from, to *unit.Unit) *big.Rat {
+	r, err := unit.ConvertExact(v, from, to)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
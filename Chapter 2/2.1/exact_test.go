@@ -0,0 +1,130 @@
+/* Property-based tests for the exact and float64 conversions, using 'testing/quick'
+to generate random values. In exact mode, every round trip between two scales must
+give back the value it started from exactly. In float mode it must do so within
+'Tolerance', and the float64 result of each conversion must agree with the exact
+one to the same tolerance. Run them with 'go test'. */
+
+package tempconv
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// randRat generates rationals with up to 18-digit numerators and denominators up
+// to 10^6, both signs, which covers values such as 273.15 and 1/3 alike.
+func randRat(values []reflect.Value, r *rand.Rand) {
+	for i := range values {
+		num := r.Int63n(1e18) - 5e17
+		den := r.Int63n(1e6) + 1
+		values[i] = reflect.ValueOf(big.NewRat(num, den))
+	}
+}
+
+// randFloat generates floats of magnitudes from 10^-3 to 10^9.
+func randFloat(values []reflect.Value, r *rand.Rand) {
+	for i := range values {
+		v := (r.Float64() - 0.5) * math.Pow(10, float64(r.Intn(12)-3))
+		values[i] = reflect.ValueOf(v)
+	}
+}
+
+var exactTrips = []struct {
+	name        string
+	there, back func(*big.Rat) *big.Rat
+}{
+	{"K->C->K", KToCExact, CToKExact},
+	{"C->K->C", CToKExact, KToCExact},
+	{"C->F->C", CToFExact, FToCExact},
+	{"F->C->F", FToCExact, CToFExact},
+	{"K->F->K", KToFExact, FToKExact},
+	{"F->K->F", FToKExact, KToFExact},
+}
+
+func TestExactRoundTrip(t *testing.T) {
+	for _, trip := range exactTrips {
+		prop := func(v *big.Rat) bool {
+			return trip.back(trip.there(v)).Cmp(v) == 0
+		}
+		if err := quick.Check(prop, &quick.Config{MaxCount: 2000, Values: randRat}); err != nil {
+			t.Errorf("%s: %v", trip.name, err)
+		}
+	}
+}
+
+func TestExactValues(t *testing.T) {
+	rat := func(s string) *big.Rat {
+		r, _ := new(big.Rat).SetString(s)
+		return r
+	}
+	tests := []struct {
+		name      string
+		got, want *big.Rat
+	}{
+		{"KToC(273.15)", KToCExact(rat("273.15")), rat("0")},
+		{"FToK(0)", FToKExact(rat("0")), rat("45967/180")},
+		{"FToK(32)", FToKExact(rat("32")), rat("273.15")},
+		{"CToF(37)", CToFExact(rat("37")), rat("98.6")},
+		{"FToC(100)", FToCExact(rat("100")), rat("340/9")},
+		{"KToF(0)", KToFExact(rat("0")), rat("-459.67")},
+	}
+	for _, test := range tests {
+		if test.got.Cmp(test.want) != 0 {
+			t.Errorf("%s = %s, expected %s", test.name, test.got.RatString(), test.want.RatString())
+		}
+	}
+}
+
+var floatTrips = []struct {
+	name string
+	trip func(float64) float64
+}{
+	{"K->C->K", func(v float64) float64 { return float64(CToK(KToC(Kelvin(v)))) }},
+	{"C->K->C", func(v float64) float64 { return float64(KToC(CToK(Celsius(v)))) }},
+	{"C->F->C", func(v float64) float64 { return float64(FToC(CToF(Celsius(v)))) }},
+	{"F->C->F", func(v float64) float64 { return float64(CToF(FToC(Fahrenheit(v)))) }},
+	{"K->F->K", func(v float64) float64 { return float64(FToK(KToF(Kelvin(v)))) }},
+	{"F->K->F", func(v float64) float64 { return float64(KToF(FToK(Fahrenheit(v)))) }},
+}
+
+// within reports whether two temperatures agree within 'Tolerance'.
+func within(a, b float64) bool {
+	return math.Abs(a-b) <= Tolerance*(math.Abs(b)+459.67)
+}
+
+func TestFloatRoundTrip(t *testing.T) {
+	for _, trip := range floatTrips {
+		prop := func(v float64) bool { return within(trip.trip(v), v) }
+		if err := quick.Check(prop, &quick.Config{MaxCount: 20000, Values: randFloat}); err != nil {
+			t.Errorf("%s: %v", trip.name, err)
+		}
+	}
+}
+
+func TestFloatMatchesExact(t *testing.T) {
+	conversions := []struct {
+		name  string
+		float func(float64) float64
+		exact func(*big.Rat) *big.Rat
+	}{
+		{"CToF", func(v float64) float64 { return float64(CToF(Celsius(v))) }, CToFExact},
+		{"FToC", func(v float64) float64 { return float64(FToC(Fahrenheit(v))) }, FToCExact},
+		{"KToC", func(v float64) float64 { return float64(KToC(Kelvin(v))) }, KToCExact},
+		{"CToK", func(v float64) float64 { return float64(CToK(Celsius(v))) }, CToKExact},
+		{"KToF", func(v float64) float64 { return float64(KToF(Kelvin(v))) }, KToFExact},
+		{"FToK", func(v float64) float64 { return float64(FToK(Fahrenheit(v))) }, FToKExact},
+	}
+	for _, c := range conversions {
+		prop := func(v float64) bool {
+			want, _ := c.exact(new(big.Rat).SetFloat64(v)).Float64()
+			return within(c.float(v), want)
+		}
+		if err := quick.Check(prop, &quick.Config{MaxCount: 5000, Values: randFloat}); err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		}
+	}
+}
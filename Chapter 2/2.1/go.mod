@@ -1,3 +1,7 @@
 module GoBookSolutions/2.1
 
 go 1.20
+
+require GoBookSolutions/3.13 v0.0.0
+
+replace GoBookSolutions/3.13 => "../../Chapter 3/3.13"
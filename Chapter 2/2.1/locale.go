@@ -0,0 +1,77 @@
+package tempconv
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+/* 'String' always writes "21.5°C", which is what the book does and what text and
+JSON use. For people, a 'Locale' formats a temperature the way their language does:
+"21,5 °C" in German and French (with a no-break space in French), to a chosen number
+of decimals, and with any degree sign, or none. The kelvin never takes a degree
+sign. There is no locale support in the standard library, so the table below only
+covers a few languages; anything else falls back to English. */
+
+// Locale describes how to format temperatures.
+type Locale struct {
+	Decimal   string // the decimal separator
+	Precision int    // the number of decimals; negative for as many as needed
+	Degree    string // written in place of '°'; empty to leave the sign out
+	Space     string // between the number and the unit
+}
+
+// Locales are the known locales, by lower-case language tag.
+var Locales = map[string]Locale{
+	"en":    {Decimal: ".", Precision: -1, Degree: "°"},
+	"en-gb": {Decimal: ".", Precision: -1, Degree: "°"},
+	"de":    {Decimal: ",", Precision: -1, Degree: "°", Space: " "},
+	"fr":    {Decimal: ",", Precision: -1, Degree: "°", Space: "\u00a0"},
+	"es":    {Decimal: ",", Precision: -1, Degree: "°", Space: " "},
+	"it":    {Decimal: ",", Precision: -1, Degree: "°", Space: " "},
+	"el":    {Decimal: ",", Precision: -1, Degree: "°", Space: " "},
+	"ja":    {Decimal: ".", Precision: -1, Degree: "°"},
+}
+
+// LocaleFor returns the locale for a tag such as "de", "fr-CA" or "de_DE.UTF-8":
+// the entry for the whole tag if there is one, else the one for its language, else
+// English.
+func LocaleFor(tag string) Locale {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if l, ok := Locales[tag]; ok {
+		return l
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	if l, ok := Locales[lang]; ok {
+		return l
+	}
+	return Locales["en"]
+}
+
+// LocaleFromEnv returns the locale named by the LC_ALL, LC_NUMERIC or LANG
+// environment variables, the first one that is set, as the C library does.
+func LocaleFromEnv() Locale {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if tag := os.Getenv(name); tag != "" {
+			return LocaleFor(tag)
+		}
+	}
+	return Locales["en"]
+}
+
+// Format writes 't' in its own scale, e.g. "21,5 °C".
+func (l Locale) Format(t Temperature) string {
+	f, u := t.value()
+	v := strconv.FormatFloat(f, 'f', l.Precision, 64)
+	if strings.Trim(v, "-0.") == "" {
+		v = strings.TrimPrefix(v, "-") // -0.04 rounds to "0.0", not "-0.0"
+	}
+	sym := u.Symbol
+	if s := strings.TrimPrefix(sym, "°"); s != sym {
+		sym = l.Degree + s
+	}
+	return strings.Replace(v, ".", l.Decimal, 1) + l.Space + sym
+}
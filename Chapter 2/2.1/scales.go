@@ -0,0 +1,73 @@
+package tempconv
+
+import (
+	"fmt"
+
+	"GoBookSolutions/2.1/unit"
+)
+
+/* Five historical scales besides the three of the book. Rather than a function for
+every pair of the eight scales, each new scale converts to and from the kelvin, the
+common base, and every type has a 'Kelvin' method; 'KToRe(c.Kelvin())' converts
+degrees Celsius to Réaumur. The 'Temperature' interface is what all eight types have
+in common, and is what the locale formatting in locale.go works with.
+
+The Delisle scale counts down from the boiling point of water, so a Delisle value
+below absolute zero is one above 559.725 °De; 'check' and 'parse' know about it. */
+
+type Rankine float64
+type Reaumur float64
+type Delisle float64
+type Newton float64
+type Romer float64
+
+func (r Rankine) String() string { return fmt.Sprintf("%g°R", r) }
+func (r Reaumur) String() string { return fmt.Sprintf("%g°Ré", r) }
+func (d Delisle) String() string { return fmt.Sprintf("%g°De", d) }
+func (n Newton) String() string  { return fmt.Sprintf("%g°N", n) }
+func (r Romer) String() string   { return fmt.Sprintf("%g°Rø", r) }
+
+// Temperature is a value on any of the scales.
+type Temperature interface {
+	fmt.Stringer
+	Kelvin() Kelvin
+	value() (float64, *unit.Unit)
+}
+
+func (c Celsius) Kelvin() Kelvin    { return CToK(c) }
+func (f Fahrenheit) Kelvin() Kelvin { return FToK(f) }
+func (k Kelvin) Kelvin() Kelvin     { return k }
+func (r Rankine) Kelvin() Kelvin    { return RToK(r) }
+func (r Reaumur) Kelvin() Kelvin    { return ReToK(r) }
+func (d Delisle) Kelvin() Kelvin    { return DeToK(d) }
+func (n Newton) Kelvin() Kelvin     { return NToK(n) }
+func (r Romer) Kelvin() Kelvin      { return RoToK(r) }
+
+func (c Celsius) value() (float64, *unit.Unit)    { return float64(c), unit.Celsius }
+func (f Fahrenheit) value() (float64, *unit.Unit) { return float64(f), unit.Fahrenheit }
+func (k Kelvin) value() (float64, *unit.Unit)     { return float64(k), unit.Kelvin }
+func (r Rankine) value() (float64, *unit.Unit)    { return float64(r), unit.Rankine }
+func (r Reaumur) value() (float64, *unit.Unit)    { return float64(r), unit.Reaumur }
+func (d Delisle) value() (float64, *unit.Unit)    { return float64(d), unit.Delisle }
+func (n Newton) value() (float64, *unit.Unit)     { return float64(n), unit.DegreeNewton }
+func (r Romer) value() (float64, *unit.Unit)      { return float64(r), unit.Romer }
+
+func RToK(r Rankine) Kelvin { return Kelvin(conv(float64(r), unit.Rankine, unit.Kelvin)) }
+
+func KToR(k Kelvin) Rankine { return Rankine(conv(float64(k), unit.Kelvin, unit.Rankine)) }
+
+func ReToK(r Reaumur) Kelvin { return Kelvin(conv(float64(r), unit.Reaumur, unit.Kelvin)) }
+
+func KToRe(k Kelvin) Reaumur { return Reaumur(conv(float64(k), unit.Kelvin, unit.Reaumur)) }
+
+func DeToK(d Delisle) Kelvin { return Kelvin(conv(float64(d), unit.Delisle, unit.Kelvin)) }
+
+func KToDe(k Kelvin) Delisle { return Delisle(conv(float64(k), unit.Kelvin, unit.Delisle)) }
+
+func NToK(n Newton) Kelvin { return Kelvin(conv(float64(n), unit.DegreeNewton, unit.Kelvin)) }
+
+func KToN(k Kelvin) Newton { return Newton(conv(float64(k), unit.Kelvin, unit.DegreeNewton)) }
+
+func RoToK(r Romer) Kelvin { return Kelvin(conv(float64(r), unit.Romer, unit.Kelvin)) }
+
+func KToRo(k Kelvin) Romer { return Romer(conv(float64(k), unit.Kelvin, unit.Romer)) }
@@ -0,0 +1,56 @@
+/* These tests check the five additional scales against the fixed points of water
+and absolute zero, and that Delisle values are checked the right way round. Run
+them with 'go test'. */
+
+package tempconv
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestScales(t *testing.T) {
+	tests := []struct {
+		name              string
+		freezing, boiling Temperature
+		zero              Temperature
+	}{
+		{"Rankine", Rankine(491.67), Rankine(671.67), Rankine(0)},
+		{"Réaumur", Reaumur(0), Reaumur(80), Reaumur(-218.52)},
+		{"Delisle", Delisle(150), Delisle(0), Delisle(559.725)},
+		{"Newton", Newton(0), Newton(33), Newton(-90.1395)},
+		{"Rømer", Romer(7.5), Romer(60), Romer(-135.90375)},
+	}
+	near := func(a, b Kelvin) bool { return math.Abs(float64(a-b)) < 1e-9 }
+	for _, test := range tests {
+		if k := test.freezing.Kelvin(); !near(k, 273.15) {
+			t.Errorf("%s: %v is %v, expected 273.15K", test.name, test.freezing, k)
+		}
+		if k := test.boiling.Kelvin(); !near(k, 373.15) {
+			t.Errorf("%s: %v is %v, expected 373.15K", test.name, test.boiling, k)
+		}
+		if k := test.zero.Kelvin(); !near(k, 0) {
+			t.Errorf("%s: %v is %v, expected 0K", test.name, test.zero, k)
+		}
+	}
+	if r := KToRe(CToK(100)); math.Abs(float64(r-80)) > 1e-12 {
+		t.Errorf("100°C = %v, expected 80°Ré", r)
+	}
+	if d := KToDe(0); d != 559.725 {
+		t.Errorf("0K = %v, expected 559.725°De", d)
+	}
+}
+
+func TestDelisleAbsoluteZero(t *testing.T) {
+	var d Delisle
+	if err := d.UnmarshalText([]byte("600")); !errors.Is(err, ErrBelowAbsoluteZero) {
+		t.Errorf("600°De: error = %v, expected ErrBelowAbsoluteZero", err)
+	}
+	if err := d.UnmarshalText([]byte("-100")); err != nil {
+		t.Errorf("-100°De: %v", err)
+	}
+	if err := d.UnmarshalText([]byte("559.725")); err != nil || d != 559.725 {
+		t.Errorf("559.725°De = %v, %v", d, err)
+	}
+}
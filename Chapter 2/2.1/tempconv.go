@@ -7,10 +7,11 @@ type Fahrenheit float64
 type Kelvin float64 // Added the Kelvin custom type
 
 const (
-	AbsoluteZeroC Celsius = -273.15
-	FreezingC     Celsius = 0
-	BoilingC      Celsius = 100
-	AbsoluteZeroK Kelvin  = 0 // Added the absolute zero temperature in Kelvin (0K)
+	AbsoluteZeroC Celsius    = -273.15
+	FreezingC     Celsius    = 0
+	BoilingC      Celsius    = 100
+	AbsoluteZeroK Kelvin     = 0 // Added the absolute zero temperature in Kelvin (0K)
+	AbsoluteZeroF Fahrenheit = -459.67
 )
 
 func (c Celsius) String() string    { return fmt.Sprintf("%g°C", c) }
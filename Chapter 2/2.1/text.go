@@ -0,0 +1,147 @@
+package tempconv
+
+import (
+	"encoding/json"
+
+	"GoBookSolutions/2.1/unit"
+)
+
+/* Every type implements 'encoding.TextMarshaler' and 'encoding.TextUnmarshaler', so
+temperatures can be kept in configuration files as "21.5°C". The text is what
+'String' returns; '%g' writes the shortest number that reads back as the same
+float64, so a value survives the round trip unchanged. Values that could not be read
+back, NaN, the infinities and temperatures below absolute zero, fail to marshal.
+Reading goes through 'parse', which accepts any scale and rejects temperatures below
+absolute zero: "70.7°F" in a 'Celsius' field is read as 21.5.
+
+'encoding/json' uses the text methods for JSON strings. 'UnmarshalJSON' also takes a
+plain JSON number, such as 21.5, in the scale of the field, and ignores null as the
+standard types do. */
+
+func (c Celsius) MarshalText() ([]byte, error) {
+	return marshalText(c.String(), float64(c), unit.Celsius)
+}
+
+func (f Fahrenheit) MarshalText() ([]byte, error) {
+	return marshalText(f.String(), float64(f), unit.Fahrenheit)
+}
+
+func (k Kelvin) MarshalText() ([]byte, error) {
+	return marshalText(k.String(), float64(k), unit.Kelvin)
+}
+
+func (r Rankine) MarshalText() ([]byte, error) {
+	return marshalText(r.String(), float64(r), unit.Rankine)
+}
+
+func (r Reaumur) MarshalText() ([]byte, error) {
+	return marshalText(r.String(), float64(r), unit.Reaumur)
+}
+
+func (d Delisle) MarshalText() ([]byte, error) {
+	return marshalText(d.String(), float64(d), unit.Delisle)
+}
+
+func (n Newton) MarshalText() ([]byte, error) {
+	return marshalText(n.String(), float64(n), unit.DegreeNewton)
+}
+
+func (r Romer) MarshalText() ([]byte, error) {
+	return marshalText(r.String(), float64(r), unit.Romer)
+}
+
+func (c *Celsius) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(c), unit.Celsius)
+}
+
+func (f *Fahrenheit) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(f), unit.Fahrenheit)
+}
+
+func (k *Kelvin) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(k), unit.Kelvin)
+}
+
+func (r *Rankine) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(r), unit.Rankine)
+}
+
+func (r *Reaumur) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(r), unit.Reaumur)
+}
+
+func (d *Delisle) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(d), unit.Delisle)
+}
+
+func (n *Newton) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(n), unit.DegreeNewton)
+}
+
+func (r *Romer) UnmarshalText(b []byte) error {
+	return unmarshalText(b, (*float64)(r), unit.Romer)
+}
+
+func (c *Celsius) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(c), unit.Celsius)
+}
+
+func (f *Fahrenheit) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(f), unit.Fahrenheit)
+}
+
+func (k *Kelvin) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(k), unit.Kelvin)
+}
+
+func (r *Rankine) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(r), unit.Rankine)
+}
+
+func (r *Reaumur) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(r), unit.Reaumur)
+}
+
+func (d *Delisle) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(d), unit.Delisle)
+}
+
+func (n *Newton) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(n), unit.DegreeNewton)
+}
+
+func (r *Romer) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, (*float64)(r), unit.Romer)
+}
+
+// marshalText returns 's', the text of 'v' in unit 'u', if 'v' is a temperature that
+// 'unmarshalText' would read back, and the error of 'check' otherwise.
+func marshalText(s string, v float64, u *unit.Unit) ([]byte, error) {
+	if err := check(s, v, u); err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// unmarshalText parses 'b' into '*v', in unit 'u'. '*v' is left alone on error.
+func unmarshalText(b []byte, v *float64, u *unit.Unit) error {
+	r, err := parse(string(b), u)
+	if err == nil {
+		*v = r
+	}
+	return err
+}
+
+func unmarshalJSON(b []byte, v *float64, u *unit.Unit) error {
+	switch {
+	case string(b) == "null":
+		return nil
+	case len(b) > 0 && b[0] == '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		b = []byte(s)
+	}
+	return unmarshalText(b, v, u)
+}
@@ -0,0 +1,105 @@
+/* These tests cover text and JSON marshaling and the locale formatting. A value
+marshaled to text or JSON must come back unchanged, and values in other scales or as
+plain JSON numbers must be read into the scale of the field. Run them with
+'go test'. */
+
+package tempconv
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	for _, v := range []Temperature{Celsius(21.5), Fahrenheit(-40), Kelvin(0.1), Rankine(500),
+		Reaumur(16), Delisle(-12.25), Newton(7), Romer(15.0000001)} {
+		b, err := v.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+		if err != nil || string(b) != v.String() {
+			t.Errorf("%v: MarshalText() = %q, %v", v, b, err)
+		}
+	}
+	for _, test := range []struct {
+		v    Temperature
+		want error
+	}{
+		{Celsius(math.NaN()), ErrNotFinite},
+		{Kelvin(math.Inf(1)), ErrNotFinite},
+		{Fahrenheit(math.Inf(-1)), ErrNotFinite},
+		{Kelvin(-1), ErrBelowAbsoluteZero},
+		{Celsius(-273.16), ErrBelowAbsoluteZero},
+		{Delisle(560), ErrBelowAbsoluteZero},
+	} {
+		b, err := test.v.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+		if !errors.Is(err, test.want) || b != nil {
+			t.Errorf("%v: MarshalText() = %q, %v; expected %v", test.v, b, err, test.want)
+		}
+	}
+	var c Celsius
+	if err := c.UnmarshalText([]byte("21.5°C")); err != nil || c != 21.5 {
+		t.Errorf("UnmarshalText(21.5°C) = %v, %v", c, err)
+	}
+	if err := c.UnmarshalText([]byte("-1K")); !errors.Is(err, ErrBelowAbsoluteZero) || c != 21.5 {
+		t.Errorf("UnmarshalText(-1K) = %v, %v; expected the old value and an error", c, err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type config struct {
+		Room    Celsius
+		Oven    Fahrenheit
+		Sensor  Kelvin
+		Missing *Newton
+	}
+	in := config{Room: 21.5, Oven: 350, Sensor: 77.355}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Room":"21.5°C","Oven":"350°F","Sensor":"77.355K","Missing":null}`; string(b) != want {
+		t.Errorf("Marshal = %s, expected %s", b, want)
+	}
+	if b, err := json.Marshal(config{Room: -300}); !errors.Is(err, ErrBelowAbsoluteZero) {
+		t.Errorf("Marshal(-300°C) = %s, %v; expected ErrBelowAbsoluteZero", b, err)
+	}
+	var out config
+	if err := json.Unmarshal(b, &out); err != nil || out != in {
+		t.Errorf("Unmarshal(%s) = %+v, %v", b, out, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Room": 20, "Oven": "100 °C", "Sensor": "-196.2C"}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Room != 20 || out.Oven != 212 || out.Sensor < 76.9 || out.Sensor > 77 {
+		t.Errorf("Unmarshal = %+v", out)
+	}
+	if err := json.Unmarshal([]byte(`{"Room": -300}`), &out); !errors.Is(err, ErrBelowAbsoluteZero) {
+		t.Errorf("Unmarshal(-300°C) error = %v, expected ErrBelowAbsoluteZero", err)
+	}
+	if err := json.Unmarshal([]byte(`{"Room": true}`), &out); err == nil {
+		t.Errorf("Unmarshal(true) succeeded")
+	}
+}
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		locale Locale
+		t      Temperature
+		want   string
+	}{
+		{LocaleFor("en_US.UTF-8"), Celsius(21.5), "21.5°C"},
+		{LocaleFor("de_DE.UTF-8"), Celsius(21.5), "21,5 °C"},
+		{LocaleFor("fr-CA"), Fahrenheit(-3.25), "-3,25\u00a0°F"},
+		{LocaleFor("el"), Kelvin(300), "300 K"},
+		{LocaleFor("xx"), Reaumur(1), "1°Ré"},
+		{Locale{Decimal: ".", Precision: 1, Degree: "°"}, Celsius(-0.04), "0.0°C"},
+		{Locale{Decimal: ",", Precision: 2, Space: " "}, Romer(7.5), "7,50 Rø"},
+		{Locale{Decimal: ".", Precision: 0, Degree: " deg "}, Delisle(150), "150 deg De"},
+	}
+	for _, test := range tests {
+		if got := test.locale.Format(test.t); got != test.want {
+			t.Errorf("Format(%v) = %q, expected %q", test.t, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dims is a dimension vector: the exponent of each base quantity in a quantity. A
+// speed is length¹·time⁻¹, a force mass¹·length¹·time⁻². Multiplying quantities
+// adds their vectors and dividing subtracts them, which is how compound units such
+// as 'kg*m/s^2' get their dimension. The information (byte) is kept as a base
+// quantity of its own, so that data sizes cannot be mixed up with plain numbers.
+type Dims [numBase]int8
+
+// The base quantities, in the order of a 'Dims' vector.
+const (
+	baseLength = iota
+	baseMass
+	baseTime
+	baseCurrent
+	baseTemperature
+	baseAmount
+	baseLuminosity
+	baseInformation
+	numBase
+)
+
+// baseSymbols are the symbols of the base units, in the order of a 'Dims' vector.
+var baseSymbols = [numBase]string{"m", "kg", "s", "A", "K", "mol", "cd", "B"}
+
+// ErrExponentRange is returned when an exponent of a dimension vector would leave the
+// range of an int8, as in 'm^200'; it would otherwise wrap around silently.
+var ErrExponentRange = errors.New("dimension exponent out of range")
+
+// Mul returns the vector of the product of two quantities.
+func (d Dims) Mul(e Dims) (Dims, error) {
+	for i := range d {
+		if err := d.set(i, int(d[i])+int(e[i])); err != nil {
+			return Dims{}, err
+		}
+	}
+	return d, nil
+}
+
+// Div returns the vector of the quotient of two quantities.
+func (d Dims) Div(e Dims) (Dims, error) {
+	for i := range d {
+		if err := d.set(i, int(d[i])-int(e[i])); err != nil {
+			return Dims{}, err
+		}
+	}
+	return d, nil
+}
+
+// Pow returns the vector of a quantity raised to the power 'n'.
+func (d Dims) Pow(n int) (Dims, error) {
+	for i := range d {
+		if d[i] == 0 {
+			continue
+		}
+		if n < math.MinInt8 || n > math.MaxInt8 {
+			return Dims{}, ErrExponentRange
+		}
+		if err := d.set(i, int(d[i])*n); err != nil {
+			return Dims{}, err
+		}
+	}
+	return d, nil
+}
+
+// set sets exponent 'i' to 'e', if it fits.
+func (d *Dims) set(i, e int) error {
+	if e < math.MinInt8 || e > math.MaxInt8 {
+		return ErrExponentRange
+	}
+	d[i] = int8(e)
+	return nil
+}
+
+// String writes the vector in base units, e.g. "kg·m/s²", or "1" for a
+// dimensionless quantity.
+func (d Dims) String() string {
+	var num, den []string
+	for _, i := range []int{baseMass, baseLength, baseTime, baseCurrent, baseTemperature,
+		baseAmount, baseLuminosity, baseInformation} {
+		switch e := d[i]; {
+		case e > 0:
+			num = append(num, baseSymbols[i]+superscript(int(e)))
+		case e < 0:
+			den = append(den, baseSymbols[i]+superscript(int(-e)))
+		}
+	}
+	s := strings.Join(num, "·")
+	if s == "" {
+		s = "1"
+	}
+	if len(den) > 0 {
+		s += "/" + strings.Join(den, "·")
+	}
+	return s
+}
+
+// superscript writes an exponent in superscript digits, leaving out 1.
+func superscript(n int) string {
+	if n == 1 {
+		return ""
+	}
+	const digits = "⁰¹²³⁴⁵⁶⁷⁸⁹"
+	var b strings.Builder
+	for _, c := range strconv.Itoa(n) {
+		b.WriteRune([]rune(digits)[c-'0'])
+	}
+	return b.String()
+}
+
+// dimVectors gives the vector of every named dimension, in the order used to name a
+// vector: when two dimensions share one (energy and torque would), the first wins.
+var dimVectors = []struct {
+	dim  Dimension
+	dims Dims
+}{
+	{Dimensionless, Dims{}},
+	{Temperature, Dims{baseTemperature: 1}},
+	{Length, Dims{baseLength: 1}},
+	{Mass, Dims{baseMass: 1}},
+	{Time, Dims{baseTime: 1}},
+	{Volume, Dims{baseLength: 3}},
+	{Area, Dims{baseLength: 2}},
+	{Speed, Dims{baseLength: 1, baseTime: -1}},
+	{Acceleration, Dims{baseLength: 1, baseTime: -2}},
+	{Frequency, Dims{baseTime: -1}},
+	{Force, Dims{baseMass: 1, baseLength: 1, baseTime: -2}},
+	{Pressure, Dims{baseMass: 1, baseLength: -1, baseTime: -2}},
+	{Energy, Dims{baseMass: 1, baseLength: 2, baseTime: -2}},
+	{Power, Dims{baseMass: 1, baseLength: 2, baseTime: -3}},
+	{Data, Dims{baseInformation: 1}},
+}
+
+// Dims returns the vector of a named dimension. Dimensions without a vector, such as
+// those of units added to a registry by hand, cannot take part in expressions.
+func (d Dimension) Dims() (Dims, bool) {
+	for _, v := range dimVectors {
+		if v.dim == d {
+			return v.dims, true
+		}
+	}
+	return Dims{}, false
+}
+
+// DimensionOf names a vector: the named dimension it belongs to, or the vector
+// written in base units if it has no name, such as "kg·m²" for a mass times an area.
+func DimensionOf(d Dims) Dimension {
+	for _, v := range dimVectors {
+		if v.dims == d {
+			return v.dim
+		}
+	}
+	return Dimension(d.String())
+}
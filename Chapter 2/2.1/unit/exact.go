@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/* Conversions in float64 round at every step: 273.15 has no exact binary
+representation, so converting kelvins to degrees Celsius and back can be off in the
+last bit. The exact mode does the same arithmetic as 'Convert' with 'big.Rat', on
+the factors as they were written. A factor is read back as the shortest decimal that
+gives the same float64 (0.3048, 273.15, 5/9 for the two parts of a fraction) when
+that decimal is short enough to have been typed in, and as its exact binary value
+otherwise, which is what the powers of two of the IEC prefixes need. With exact
+factors every conversion is exact, and so is every round trip. */
+
+// exactDigits is the number of significant digits above which a factor is taken as
+// computed rather than written, and read as its exact binary value.
+const exactDigits = 15
+
+// ExactFactor returns the rational number a float64 factor stands for; see above.
+func ExactFactor(f float64) *big.Rat {
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mant := strings.TrimLeft(s[:strings.IndexByte(s, 'e')], "-")
+	if digits := len(strings.Replace(mant, ".", "", 1)); digits > exactDigits {
+		return new(big.Rat).SetFloat64(f)
+	}
+	r, _ := new(big.Rat).SetString(s)
+	return r
+}
+
+// ConvertExact converts 'v' from unit 'from' to unit 'to' exactly. It does not
+// modify 'v'.
+func ConvertExact(v *big.Rat, from, to *Unit) (*big.Rat, error) {
+	if from.Dim != to.Dim {
+		return nil, &DimensionError{from, to}
+	}
+	r := new(big.Rat).Add(v, ExactFactor(from.Offset))
+	r.Mul(r, ExactFactor(from.Num))
+	r.Mul(r, ExactFactor(to.den()))
+	r.Quo(r, ExactFactor(from.den()))
+	r.Quo(r, ExactFactor(to.Num))
+	return r.Sub(r, ExactFactor(to.Offset)), nil
+}
@@ -0,0 +1,425 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/* Expressions multiply and divide quantities and units, such as '9.81 kg*m/s^2' or
+'5 kWh / 2 h'. The grammar is
+
+	expr    = term { ('*' | '×' | '·' | '/') term }
+	term    = power { power }
+	power   = primary [ '^' ['-'] integer ]
+	primary = number | unit | '(' expr ')' | '-' primary
+
+Writing two factors next to each other multiplies them, and binds tighter than '*'
+and '/', so '5 kWh / 2 h' is (5 kWh) / (2 h) as one would read it. A unit is looked
+up in the registry, trying runs of up to three words first ('fl oz'); if that fails,
+trailing digits or superscripts are read as an exponent, so 's2' and 's²' are 's^2'.
+Every value is carried in base units together with its dimension vector, which
+multiplication and division add up and subtract.
+
+Affine units (°C, °F) have no meaning inside a product: 2 °C is not twice 1 °C. They
+are only accepted on their own, as in '72 °F'. */
+
+// ErrDivisionByZero is returned when an expression divides by a zero quantity, as in
+// '1 m / 0 s', or raises one to a negative power; the result would be infinite.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Quantity is a value in a unit.
+type Quantity struct {
+	Value float64
+	Unit  *Unit
+}
+
+func (q Quantity) String() string {
+	return strconv.FormatFloat(q.Value, 'g', -1, 64) + " " + q.Unit.Symbol
+}
+
+// In converts the quantity to another unit of the same dimension.
+func (q Quantity) In(u *Unit) (Quantity, error) {
+	v, err := Convert(q.Value, q.Unit, u)
+	return Quantity{v, u}, err
+}
+
+// Eval evaluates an expression. A single quantity such as '72 °F' keeps its unit;
+// anything else is returned in the registry's unit for the resulting dimension whose
+// factor is one (N for a force, J for an energy), or in base units if there is none.
+func (r *Registry) Eval(s string) (Quantity, error) {
+	toks, err := tokenize(s)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if len(toks) > 0 && toks[0].kind == tokNumber {
+		if u, ok := r.singleUnit(toks[1:]); ok {
+			return Quantity{toks[0].num, u}, nil
+		}
+	}
+	p := &parser{r: r, src: s, toks: toks}
+	v, err := p.parse()
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{v.v, r.BaseUnit(v.d)}, nil
+}
+
+// ParseExpr parses a unit expression such as 'kg*m/s^2' or 'km/h' into a unit.
+// A name the registry knows is returned as is; anything else becomes a new unit,
+// with the expression as its symbol, that is not added to the registry.
+func (r *Registry) ParseExpr(s string) (*Unit, error) {
+	toks, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if u, ok := r.singleUnit(toks); ok {
+		return u, nil
+	}
+	p := &parser{r: r, src: s, toks: toks}
+	v, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	d := v.d
+	return &Unit{Symbol: strings.Join(strings.Fields(s), " "), Dim: DimensionOf(d), Num: v.v, vec: &d}, nil
+}
+
+// singleUnit reports whether the tokens, all words, name exactly one unit.
+func (r *Registry) singleUnit(toks []token) (*Unit, bool) {
+	if len(toks) == 0 {
+		return nil, false
+	}
+	words := make([]string, len(toks))
+	for i, t := range toks {
+		if t.kind != tokWord {
+			return nil, false
+		}
+		words[i] = t.text
+	}
+	return r.Lookup(strings.Join(words, " "))
+}
+
+// BaseUnit returns the unit a value of dimension vector 'd' is expressed in by
+// 'Eval': the registered unit of that dimension with a factor of one, or a new unit
+// named after the base units.
+func (r *Registry) BaseUnit(d Dims) *Unit {
+	dim := DimensionOf(d)
+	for _, u := range r.byDim[dim] {
+		if u.Num == 1 && u.den() == 1 && !u.Affine() {
+			return u
+		}
+	}
+	return &Unit{Symbol: d.String(), Dim: dim, Num: 1, vec: &d}
+}
+
+type tokKind int
+
+const (
+	tokNumber tokKind = iota
+	tokWord
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	num  float64
+}
+
+var numberRE = regexp.MustCompile(`^(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?`)
+
+// tokenize splits an expression into numbers, words and operators.
+func tokenize(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		c, size := utf8.DecodeRuneInString(rest)
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case strings.ContainsRune("*×·/^()-+", c):
+			toks = append(toks, token{kind: tokOp, text: rest[:size]})
+			i += size
+		case c >= '0' && c <= '9' || c == '.':
+			m := numberRE.FindString(rest)
+			if m == "" {
+				return nil, fmt.Errorf("%s: invalid number at %q", s, rest)
+			}
+			n, err := strconv.ParseFloat(m, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid number %q", s, m)
+			}
+			toks = append(toks, token{kind: tokNumber, text: m, num: n})
+			i += len(m)
+		case isWordRune(c):
+			j := i
+			for j < len(s) {
+				c, size := utf8.DecodeRuneInString(s[j:])
+				if !isWordRune(c) && !(c >= '0' && c <= '9') {
+					break
+				}
+				j += size
+			}
+			toks = append(toks, token{kind: tokWord, text: s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("%s: unexpected %q", s, c)
+		}
+	}
+	return toks, nil
+}
+
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || strings.ContainsRune("°µμ²³¹⁰⁴⁵⁶⁷⁸⁹'\"%_℃℉ℓ", c)
+}
+
+// value is an intermediate result: a number in base units and its dimension.
+type value struct {
+	v float64
+	d Dims
+}
+
+type parser struct {
+	r    *Registry
+	src  string
+	toks []token
+	pos  int
+}
+
+func (p *parser) parse() (value, error) {
+	if len(p.toks) == 0 {
+		return value{}, fmt.Errorf("empty expression")
+	}
+	v, err := p.expr()
+	if err != nil {
+		return value{}, err
+	}
+	if p.pos < len(p.toks) {
+		return value{}, fmt.Errorf("%s: unexpected %q", p.src, p.toks[p.pos].text)
+	}
+	return v, nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos], true
+	}
+	return token{}, false
+}
+
+func (p *parser) isOp(ops string) (string, bool) {
+	t, ok := p.peek()
+	if !ok || t.kind != tokOp || !strings.Contains(ops, t.text) {
+		return "", false
+	}
+	return t.text, true
+}
+
+func (p *parser) expr() (value, error) {
+	v, err := p.term()
+	if err != nil {
+		return value{}, err
+	}
+	for {
+		op, ok := p.isOp("*×·/")
+		if !ok {
+			return v, nil
+		}
+		p.pos++
+		w, err := p.term()
+		if err != nil {
+			return value{}, err
+		}
+		var d Dims
+		if op == "/" {
+			if w.v == 0 {
+				return value{}, fmt.Errorf("%s: %w", p.src, ErrDivisionByZero)
+			}
+			d, err = v.d.Div(w.d)
+			v.v /= w.v
+		} else {
+			d, err = v.d.Mul(w.d)
+			v.v *= w.v
+		}
+		if err != nil {
+			return value{}, fmt.Errorf("%s: %w", p.src, err)
+		}
+		v.d = d
+	}
+}
+
+func (p *parser) term() (value, error) {
+	v, err := p.power()
+	if err != nil {
+		return value{}, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == tokOp && t.text != "(" {
+			return v, nil
+		}
+		w, err := p.power()
+		if err != nil {
+			return value{}, err
+		}
+		d, err := v.d.Mul(w.d)
+		if err != nil {
+			return value{}, fmt.Errorf("%s: %w", p.src, err)
+		}
+		v = value{v.v * w.v, d}
+	}
+}
+
+func (p *parser) power() (value, error) {
+	v, err := p.primary()
+	if err != nil {
+		return value{}, err
+	}
+	if _, ok := p.isOp("^"); !ok {
+		return v, nil
+	}
+	p.pos++
+	sign := 1
+	if op, ok := p.isOp("-+"); ok {
+		if op == "-" {
+			sign = -1
+		}
+		p.pos++
+	}
+	t, ok := p.peek()
+	if !ok || t.kind != tokNumber || t.num != math.Trunc(t.num) {
+		return value{}, fmt.Errorf("%s: expected an integer exponent after '^'", p.src)
+	}
+	p.pos++
+	// An exponent outside the range of a 'Dims' entry is rejected here, before it
+	// is converted to an int at all.
+	n := float64(sign) * t.num
+	if n < math.MinInt8 || n > math.MaxInt8 {
+		return value{}, fmt.Errorf("%s: %w: ^%v", p.src, ErrExponentRange, n)
+	}
+	return p.pow(v, int(n))
+}
+
+func (p *parser) pow(v value, n int) (value, error) {
+	if v.v == 0 && n < 0 {
+		return value{}, fmt.Errorf("%s: %w", p.src, ErrDivisionByZero)
+	}
+	d, err := v.d.Pow(n)
+	if err != nil {
+		return value{}, fmt.Errorf("%s: %w", p.src, err)
+	}
+	return value{math.Pow(v.v, float64(n)), d}, nil
+}
+
+func (p *parser) primary() (value, error) {
+	t, ok := p.peek()
+	if !ok {
+		return value{}, fmt.Errorf("%s: unexpected end of expression", p.src)
+	}
+	switch {
+	case t.kind == tokNumber:
+		p.pos++
+		return value{v: t.num}, nil
+	case t.kind == tokWord:
+		return p.unit()
+	case t.text == "(":
+		p.pos++
+		v, err := p.expr()
+		if err != nil {
+			return value{}, err
+		}
+		if _, ok := p.isOp(")"); !ok {
+			return value{}, fmt.Errorf("%s: missing ')'", p.src)
+		}
+		p.pos++
+		return v, nil
+	case t.text == "-":
+		p.pos++
+		v, err := p.primary()
+		v.v = -v.v
+		return v, err
+	}
+	return value{}, fmt.Errorf("%s: unexpected %q", p.src, t.text)
+}
+
+// unit reads the longest run of words naming a unit.
+func (p *parser) unit() (value, error) {
+	for n := 3; n > 1; n-- {
+		if p.pos+n > len(p.toks) {
+			continue
+		}
+		if u, ok := p.r.singleUnit(p.toks[p.pos : p.pos+n]); ok {
+			p.pos += n
+			return p.unitValue(u)
+		}
+	}
+	word := p.toks[p.pos].text
+	p.pos++
+	if u, ok := p.r.Lookup(word); ok {
+		return p.unitValue(u)
+	}
+	stem, exp := splitExponent(word)
+	if exp != 0 {
+		if u, ok := p.r.Lookup(stem); ok {
+			v, err := p.unitValue(u)
+			if err != nil {
+				return value{}, err
+			}
+			return p.pow(v, exp)
+		}
+	}
+	_, err := p.r.Parse(word)
+	return value{}, err
+}
+
+func (p *parser) unitValue(u *Unit) (value, error) {
+	if u.Affine() {
+		return value{}, fmt.Errorf("%s: %s is an affine unit and can only be converted on its own", p.src, u.Symbol)
+	}
+	d, ok := u.Dims()
+	if !ok {
+		return value{}, fmt.Errorf("%s: unit %s has no dimension vector", p.src, u.Symbol)
+	}
+	return value{u.Num / u.den(), d}, nil
+}
+
+// splitExponent splits trailing digits or superscript digits off a word: "s2" and
+// "s²" become "s" and 2. The exponent is 0 if there is none.
+func splitExponent(word string) (string, int) {
+	const supers = "⁰¹²³⁴⁵⁶⁷⁸⁹"
+	runes := []rune(word)
+	i := len(runes)
+	exp, scale := 0, 1
+	for i > 1 {
+		c := runes[i-1]
+		d := -1
+		if c >= '0' && c <= '9' {
+			d = int(c - '0')
+		} else if k := strings.IndexRune(supers, c); k >= 0 {
+			d = len([]rune(supers[:k]))
+		}
+		if d < 0 {
+			break
+		}
+		// Past what a 'Dims' entry holds, we only need to know the exponent is too
+		// large, and stop the arithmetic before it overflows.
+		exp += d * scale
+		if exp > math.MaxInt8 {
+			exp = math.MaxInt8 + 1
+		}
+		if scale <= math.MaxInt8 {
+			scale *= 10
+		}
+		i--
+	}
+	if i == len(runes) {
+		return word, 0
+	}
+	return string(runes[:i]), exp
+}
@@ -0,0 +1,152 @@
+// These tests evaluate unit expressions and convert the results, check that the
+// result of a computation is expressed in the matching named unit, and that
+// mismatched dimensions and malformed expressions are reported. Run them with
+// 'go test ./...' in the '2.1' directory.
+
+package unit
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr, to string
+		want     float64
+	}{
+		{"60 mph", "m/s", 26.8224},
+		{"9.81 kg*m/s^2", "N", 9.81},
+		{"9.81 kg·m/s²", "N", 9.81},
+		{"5 kWh / 2 h", "W", 2500},
+		{"5 kWh / 2 h", "kW", 2.5},
+		{"100 km / 2 h", "km/h", 50},
+		{"3 m * 4 m", "m²", 12},
+		{"1 acre * 1 ft", "gal", 325851.4285714},
+		{"1 N*m", "J", 1},
+		{"1 J/s", "W", 1},
+		{"2 (m/s)^2 * 3 kg", "J", 6},
+		{"1 / 1 ms", "Hz", 1000},
+		{"1 GiB / 8 s", "Mbit/s", 1073.741824},
+		{"72 °F", "°C", 22.2222222222222},
+		{"1 fl oz * 128", "gal", 1},
+		{"10 m/s2 * 2 s", "m/s", 20},
+		{"-3 m", "ft", -9.842519685},
+		{"1 lbf", "kg*m/s^2", 4.4482216152605},
+	}
+	for _, test := range tests {
+		q, err := Default.Eval(test.expr)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", test.expr, err)
+			continue
+		}
+		to, err := Default.ParseExpr(test.to)
+		if err != nil {
+			t.Errorf("ParseExpr(%q): %v", test.to, err)
+			continue
+		}
+		got, err := q.In(to)
+		if err != nil {
+			t.Errorf("%s in %s: %v", test.expr, test.to, err)
+			continue
+		}
+		if math.Abs(got.Value-test.want) > 1e-9*math.Max(1, math.Abs(test.want)) {
+			t.Errorf("%s in %s = %v, expected %v", test.expr, test.to, got.Value, test.want)
+		}
+	}
+}
+
+func TestEvalUnit(t *testing.T) {
+	tests := []struct{ expr, unit string }{
+		{"9.81 kg*m/s^2", "N"},
+		{"5 kWh / 2 h", "W"},
+		{"3 m * 4 m", "m²"},
+		{"2 kg * 3 m²", "kg·m²"},
+		{"4 m / 2 m", "1"},
+	}
+	for _, test := range tests {
+		q, err := Default.Eval(test.expr)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", test.expr, err)
+			continue
+		}
+		if q.Unit.Symbol != test.unit {
+			t.Errorf("Eval(%q) is in %s, expected %s", test.expr, q.Unit.Symbol, test.unit)
+		}
+	}
+	if q, _ := Default.Eval("72 °F"); q.Unit != Fahrenheit || q.Value != 72 {
+		t.Errorf("Eval(72 °F) = %v, expected 72 °F", q)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct{ expr, to, err string }{
+		{"60 mph", "kg", "cannot convert mph (speed) to kg (mass)"},
+		{"9.81 kg*m/s^2", "W", "cannot convert N (force) to W (power)"},
+		{"5 kWh / 2 h", "J", "cannot convert W (power) to J (energy)"},
+		{"2 °C * 3", "", "affine unit"},
+		{"3 m + 4 m", "", `unexpected "+"`},
+		{"(3 m", "", "missing ')'"},
+		{"3 m^x", "", "integer exponent"},
+		{"3 mtr/s", "", `unknown unit "mtr"`},
+		{"3 kg $", "", `unexpected '$'`},
+		{"5 m^200", "", "exponent out of range"},
+		{"5 m^100*m^100", "", "exponent out of range"},
+		{"5 m^100 m^100", "", "exponent out of range"},
+		{"1 / m^100 / m^100", "", "exponent out of range"},
+		{"5 m^1e30", "", "exponent out of range"},
+		{"5 m200", "", "exponent out of range"},
+		{"5 m99999999999999999999", "", "exponent out of range"},
+		{"1 m / 0 s", "", "division by zero"},
+		{"1 m / (0 s)", "", "division by zero"},
+		{"(0 m)^-2", "", "division by zero"},
+	}
+	for _, test := range tests {
+		q, err := Default.Eval(test.expr)
+		if err == nil && test.to != "" {
+			var to *Unit
+			if to, err = Default.ParseExpr(test.to); err == nil {
+				_, err = q.In(to)
+			}
+		}
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s in %s: error = %v, expected one containing %q", test.expr, test.to, err, test.err)
+		}
+	}
+	_, err := Default.Eval("9.81 kg*m/s^2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, _ := Default.ParseExpr("kg*m/s")
+	_, err = Convert(1, Newton, to)
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) {
+		t.Errorf("N to kg*m/s: error = %v, expected a *DimensionError", err)
+	}
+}
+
+func TestDims(t *testing.T) {
+	force, _ := Force.Dims()
+	if got := force.String(); got != "kg·m/s²" {
+		t.Errorf("force = %s, expected kg·m/s²", got)
+	}
+	speed, _ := Speed.Dims()
+	time, _ := Time.Dims()
+	if d, err := speed.Mul(time); err != nil || DimensionOf(d) != Length {
+		t.Errorf("speed × time = %s, %v; expected length", DimensionOf(d), err)
+	}
+	energy, _ := Energy.Dims()
+	if d, err := energy.Div(time); err != nil || DimensionOf(d) != Power {
+		t.Errorf("energy / time = %s, %v; expected power", DimensionOf(d), err)
+	}
+	length, _ := Length.Dims()
+	if _, err := length.Pow(200); !errors.Is(err, ErrExponentRange) {
+		t.Errorf("length^200: error = %v, expected ErrExponentRange", err)
+	}
+	big, _ := length.Pow(100)
+	if _, err := big.Mul(big); !errors.Is(err, ErrExponentRange) {
+		t.Errorf("length^100 × length^100: error = %v, expected ErrExponentRange", err)
+	}
+}
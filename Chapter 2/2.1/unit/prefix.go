@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"math"
+
+	"GoBookSolutions/3.13/bytesize"
+)
+
+// Prefix is an SI prefix, a power of ten applied to a unit, or a binary (IEC)
+// prefix, a power of two.
+type Prefix struct {
+	Symbol  string
+	Name    string
+	Exp     int      // the power of ten
+	Factor  float64  // used instead of 'Exp' when non-zero
+	Aliases []string // other symbols, such as "u" for micro
+}
+
+// SI lists the SI prefixes from quetta to quecto.
+var SI = []Prefix{
+	{Symbol: "Q", Name: "quetta", Exp: 30},
+	{Symbol: "R", Name: "ronna", Exp: 27},
+	{Symbol: "Y", Name: "yotta", Exp: 24},
+	{Symbol: "Z", Name: "zetta", Exp: 21},
+	{Symbol: "E", Name: "exa", Exp: 18},
+	{Symbol: "P", Name: "peta", Exp: 15},
+	{Symbol: "T", Name: "tera", Exp: 12},
+	{Symbol: "G", Name: "giga", Exp: 9},
+	{Symbol: "M", Name: "mega", Exp: 6},
+	{Symbol: "k", Name: "kilo", Exp: 3},
+	{Symbol: "h", Name: "hecto", Exp: 2},
+	{Symbol: "da", Name: "deca", Exp: 1, Aliases: []string{"deka"}},
+	{Symbol: "d", Name: "deci", Exp: -1},
+	{Symbol: "c", Name: "centi", Exp: -2},
+	{Symbol: "m", Name: "milli", Exp: -3},
+	{Symbol: "µ", Name: "micro", Exp: -6, Aliases: []string{"u", "μ"}},
+	{Symbol: "n", Name: "nano", Exp: -9},
+	{Symbol: "p", Name: "pico", Exp: -12},
+	{Symbol: "f", Name: "femto", Exp: -15},
+	{Symbol: "a", Name: "atto", Exp: -18},
+	{Symbol: "z", Name: "zepto", Exp: -21},
+	{Symbol: "y", Name: "yocto", Exp: -24},
+	{Symbol: "r", Name: "ronto", Exp: -27},
+	{Symbol: "q", Name: "quecto", Exp: -30},
+}
+
+// IEC lists the binary prefixes for data sizes, kibi (2^10) to yobi (2^80). They
+// are the multiples defined in exercise 3.13.
+var IEC = []Prefix{
+	{Symbol: "Ki", Name: "kibi", Factor: float64(bytesize.KB)},
+	{Symbol: "Mi", Name: "mebi", Factor: float64(bytesize.MB)},
+	{Symbol: "Gi", Name: "gibi", Factor: float64(bytesize.GB)},
+	{Symbol: "Ti", Name: "tebi", Factor: float64(bytesize.TB)},
+	{Symbol: "Pi", Name: "pebi", Factor: float64(bytesize.PB)},
+	{Symbol: "Ei", Name: "exbi", Factor: float64(bytesize.EB)},
+	{Symbol: "Zi", Name: "zebi", Factor: float64(bytesize.ZB)},
+	{Symbol: "Yi", Name: "yobi", Factor: float64(bytesize.YB)},
+}
+
+// multiples returns the prefixes that make a unit at least a thousand times larger.
+// Data sizes use only these: there are no millibytes, and "dB" is the decibel.
+func multiples(prefixes []Prefix) []Prefix {
+	var out []Prefix
+	for _, p := range prefixes {
+		if p.Factor >= 1000 || p.Factor == 0 && p.Exp >= 3 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Apply returns the unit 'u' scaled by the prefix, e.g. the kilometre for the metre.
+// The power of ten multiplies the numerator or the denominator of the factor, so
+// that a millimetre is exactly 1/1000 of a metre rather than 0.001 rounded to
+// binary. Names and aliases get the prefix name, symbols the prefix symbol.
+func (p Prefix) Apply(u *Unit) *Unit {
+	v := &Unit{
+		Symbol: p.Symbol + u.Symbol,
+		Name:   p.Name + u.Name,
+		Dim:    u.Dim,
+		Num:    u.Num,
+		Den:    u.den(),
+	}
+	switch scale := math.Pow10(abs(p.Exp)); {
+	case p.Factor != 0:
+		v.Num *= p.Factor
+	case p.Exp > 0:
+		v.Num *= scale
+	default:
+		v.Den *= scale
+	}
+	for _, a := range u.AltSymbols {
+		v.AltSymbols = append(v.AltSymbols, p.Symbol+a)
+	}
+	for _, a := range u.Aliases {
+		v.Aliases = append(v.Aliases, p.Name+a)
+	}
+	for _, s := range p.Aliases {
+		v.AltSymbols = append(v.AltSymbols, s+u.Symbol)
+	}
+	return v
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
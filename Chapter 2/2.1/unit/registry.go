@@ -0,0 +1,168 @@
+package unit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Registry holds units by dimension and looks them up by symbol, name or alias.
+type Registry struct {
+	byName  map[string]*Unit // symbols, names and aliases as written
+	byLower map[string]*Unit // names and aliases in lower case
+	byDim   map[Dimension][]*Unit
+	dims    []Dimension
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		byName:  make(map[string]*Unit),
+		byLower: make(map[string]*Unit),
+		byDim:   make(map[Dimension][]*Unit),
+	}
+}
+
+// UnknownUnitError is returned when a registry has no unit by the given name.
+// 'Suggestions' holds the closest names it does know, if any.
+type UnknownUnitError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownUnitError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("unknown unit %q", e.Name)
+	}
+	return fmt.Sprintf("unknown unit %q; did you mean %s?", e.Name, quoteList(e.Suggestions))
+}
+
+// Add registers a unit. It fails if the unit has no factor or if its symbol, name
+// or one of its aliases already belongs to another unit.
+func (r *Registry) Add(u *Unit) error {
+	if u.Num == 0 {
+		return fmt.Errorf("unit %s has no factor", u.Symbol)
+	}
+	for _, s := range u.names() {
+		if v, ok := r.byName[s]; ok {
+			return fmt.Errorf("unit %s: %q is already taken by %s", u.Symbol, s, v.Symbol)
+		}
+	}
+	r.add(u)
+	return nil
+}
+
+// AddPrefixed registers a unit together with its prefixed versions. A prefixed unit
+// that would clash with a unit already registered is left out, so that for example
+// the "min" of the minute is not taken over by a milli-inch. Affine units cannot be
+// prefixed, since "a kilo degree Celsius" has no sensible meaning.
+func (r *Registry) AddPrefixed(u *Unit, prefixes []Prefix) error {
+	if u.Affine() {
+		return fmt.Errorf("affine unit %s cannot be prefixed", u.Symbol)
+	}
+	if err := r.Add(u); err != nil {
+		return err
+	}
+outer:
+	for _, p := range prefixes {
+		v := p.Apply(u)
+		for _, s := range v.names() {
+			if _, ok := r.byName[s]; ok {
+				continue outer
+			}
+		}
+		r.add(v)
+	}
+	return nil
+}
+
+func (r *Registry) add(u *Unit) {
+	for _, s := range u.names() {
+		r.byName[s] = u
+		if !u.isSymbol(s) {
+			if _, ok := r.byLower[strings.ToLower(s)]; !ok {
+				r.byLower[strings.ToLower(s)] = u
+			}
+		}
+	}
+	if _, ok := r.byDim[u.Dim]; !ok {
+		r.dims = append(r.dims, u.Dim)
+	}
+	r.byDim[u.Dim] = append(r.byDim[u.Dim], u)
+}
+
+func (u *Unit) names() []string {
+	names := append([]string{u.Symbol}, u.AltSymbols...)
+	names = append(names, u.Aliases...)
+	if u.Name != "" {
+		names = append(names, u.Name)
+	}
+	return names
+}
+
+func (u *Unit) isSymbol(s string) bool {
+	if s == u.Symbol {
+		return true
+	}
+	for _, a := range u.AltSymbols {
+		if s == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup finds a unit by its symbol, name or one of its aliases. Symbols must match
+// exactly, because case tells 'mm' from 'Mm', while names and aliases are matched
+// regardless of case.
+func (r *Registry) Lookup(s string) (*Unit, bool) {
+	if u, ok := r.byName[s]; ok {
+		return u, true
+	}
+	u, ok := r.byLower[strings.ToLower(s)]
+	return u, ok
+}
+
+// Units returns the units of a dimension in the order they were registered.
+func (r *Registry) Units(d Dimension) []*Unit {
+	return append([]*Unit(nil), r.byDim[d]...)
+}
+
+// Dimensions returns the dimensions that have units, in the order they were first
+// registered.
+func (r *Registry) Dimensions() []Dimension {
+	return append([]Dimension(nil), r.dims...)
+}
+
+// Convert converts 'v' between two units given by symbol, name or alias.
+func (r *Registry) Convert(v float64, from, to string) (float64, error) {
+	f, err := r.Parse(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := r.Parse(to)
+	if err != nil {
+		return 0, err
+	}
+	return Convert(v, f, t)
+}
+
+// mustAdd is used while building 'Default', where a clash is a programming error.
+func (r *Registry) mustAdd(u *Unit, prefixes []Prefix) *Unit {
+	var err error
+	if prefixes != nil {
+		err = r.AddPrefixed(u, prefixes)
+	} else {
+		err = r.Add(u)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func (r *Registry) mustLookup(s string) *Unit {
+	u, ok := r.Lookup(s)
+	if !ok {
+		panic(&UnknownUnitError{Name: s})
+	}
+	return u
+}
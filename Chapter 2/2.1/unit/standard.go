@@ -0,0 +1,212 @@
+package unit
+
+// Default is the registry of the standard units below, with SI prefixes wherever
+// they make sense. The factors are the exact ones from the international definitions
+// (a foot is 0.3048 m and a pound 0.45359237 kg by definition), not rounded values
+// like 3.28084 feet per metre.
+var Default = NewRegistry()
+
+// Temperature; the base unit is the kelvin.
+var (
+	Kelvin = Default.mustAdd(&Unit{Symbol: "K", Name: "kelvin", Aliases: []string{"kelvins"},
+		Dim: Temperature, Num: 1}, SI)
+	Celsius = Default.mustAdd(&Unit{Symbol: "°C", Name: "degree Celsius",
+		Aliases: []string{"C", "degC", "℃", "celsius", "degrees Celsius"},
+		Dim:     Temperature, Num: 1, Offset: 273.15}, nil)
+	Fahrenheit = Default.mustAdd(&Unit{Symbol: "°F", Name: "degree Fahrenheit",
+		Aliases: []string{"F", "degF", "℉", "fahrenheit", "degrees Fahrenheit"},
+		Dim:     Temperature, Num: 5, Den: 9, Offset: 459.67}, nil)
+	Rankine = Default.mustAdd(&Unit{Symbol: "°R", Name: "degree Rankine",
+		Aliases: []string{"°Ra", "degR", "rankine", "degrees Rankine"},
+		Dim:     Temperature, Num: 5, Den: 9}, nil)
+	Reaumur = Default.mustAdd(&Unit{Symbol: "°Ré", Name: "degree Réaumur",
+		Aliases: []string{"°Re", "réaumur", "reaumur", "degree Reaumur", "degrees Réaumur", "degrees Reaumur"},
+		Dim:     Temperature, Num: 5, Den: 4, Offset: 218.52}, nil)
+	// The Delisle scale runs backwards from 0 at the boiling point of water, which the
+	// negative factor expresses: absolute zero is at +559.725 °De.
+	Delisle = Default.mustAdd(&Unit{Symbol: "°De", Name: "degree Delisle",
+		Aliases: []string{"delisle", "degrees Delisle"},
+		Dim:     Temperature, Num: -2, Den: 3, Offset: -559.725}, nil)
+	// DegreeNewton is named so as not to clash with the newton, the unit of force.
+	DegreeNewton = Default.mustAdd(&Unit{Symbol: "°N", Name: "degree Newton",
+		Aliases: []string{"degrees Newton"},
+		Dim:     Temperature, Num: 100, Den: 33, Offset: 90.1395}, nil)
+	Romer = Default.mustAdd(&Unit{Symbol: "°Rø", Name: "degree Rømer",
+		Aliases: []string{"°Ro", "rømer", "romer", "degree Romer", "degrees Rømer", "degrees Romer"},
+		Dim:     Temperature, Num: 40, Den: 21, Offset: 135.90375}, nil)
+)
+
+// Length; the base unit is the metre.
+var (
+	Metre = Default.mustAdd(&Unit{Symbol: "m", Name: "metre",
+		Aliases: []string{"meter", "metres", "meters"}, Dim: Length, Num: 1}, SI)
+	Kilometre  = Default.mustLookup("km")
+	Centimetre = Default.mustLookup("cm")
+	Millimetre = Default.mustLookup("mm")
+	Inch       = Default.mustAdd(&Unit{Symbol: "in", Name: "inch", Aliases: []string{"inches", `"`},
+		Dim: Length, Num: 0.0254}, nil)
+	Foot = Default.mustAdd(&Unit{Symbol: "ft", Name: "foot", Aliases: []string{"feet", "'"},
+		Dim: Length, Num: 0.3048}, nil)
+	Yard = Default.mustAdd(&Unit{Symbol: "yd", Name: "yard", Aliases: []string{"yards"},
+		Dim: Length, Num: 0.9144}, nil)
+	Mile = Default.mustAdd(&Unit{Symbol: "mi", Name: "mile", Aliases: []string{"miles"},
+		Dim: Length, Num: 1609.344}, nil)
+)
+
+// Mass; the base unit is the kilogram, but the prefixes go on the gram.
+var (
+	Gram = Default.mustAdd(&Unit{Symbol: "g", Name: "gram", Aliases: []string{"grams", "gramme", "grammes"},
+		Dim: Mass, Num: 1, Den: 1000}, SI)
+	Kilogram  = Default.mustLookup("kg")
+	Milligram = Default.mustLookup("mg")
+	Tonne     = Default.mustAdd(&Unit{Symbol: "t", Name: "tonne", Aliases: []string{"tonnes", "metric ton"},
+		Dim: Mass, Num: 1000}, nil)
+	Ounce = Default.mustAdd(&Unit{Symbol: "oz", Name: "ounce", Aliases: []string{"ounces"},
+		Dim: Mass, Num: 0.028349523125}, nil)
+	Pound = Default.mustAdd(&Unit{Symbol: "lb", Name: "pound", Aliases: []string{"lbs", "pounds"},
+		Dim: Mass, Num: 0.45359237}, nil)
+	Stone = Default.mustAdd(&Unit{Symbol: "st", Name: "stone", Aliases: []string{"stones"},
+		Dim: Mass, Num: 6.35029318}, nil)
+)
+
+// Volume; the base unit is the cubic metre, and the prefixes go on the litre.
+var (
+	CubicMetre = Default.mustAdd(&Unit{Symbol: "m³", AltSymbols: []string{"m3", "m^3"}, Name: "cubic metre",
+		Aliases: []string{"cubic meter", "cubic metres", "cubic meters"}, Dim: Volume, Num: 1}, nil)
+	Litre = Default.mustAdd(&Unit{Symbol: "L", AltSymbols: []string{"l", "ℓ"}, Name: "litre",
+		Aliases: []string{"liter", "litres", "liters"}, Dim: Volume, Num: 1, Den: 1000}, SI)
+	Millilitre      = Default.mustLookup("mL")
+	CubicCentimetre = Default.mustAdd(&Unit{Symbol: "cm³", AltSymbols: []string{"cm3", "cc"}, Name: "cubic centimetre",
+		Aliases: []string{"cubic centimeter", "cubic centimetres", "cubic centimeters"}, Dim: Volume, Num: 1, Den: 1e6}, nil)
+	// The US customary units are based on the gallon of 231 cubic inches.
+	USGallon = Default.mustAdd(&Unit{Symbol: "gal", Name: "US gallon",
+		Aliases: []string{"gallon", "gallons", "US gallons", "US gal"}, Dim: Volume, Num: 0.003785411784}, nil)
+	USCup = Default.mustAdd(&Unit{Symbol: "cup", Name: "US cup", Aliases: []string{"cups", "US cups"},
+		Dim: Volume, Num: 0.003785411784, Den: 16}, nil)
+	USFluidOunce = Default.mustAdd(&Unit{Symbol: "fl oz", Name: "US fluid ounce",
+		Aliases: []string{"fluid ounce", "fluid ounces", "US fl oz"}, Dim: Volume, Num: 0.003785411784, Den: 128}, nil)
+	ImperialGallon = Default.mustAdd(&Unit{Symbol: "imp gal", Name: "imperial gallon",
+		Aliases: []string{"imperial gallons", "UK gallon", "UK gallons"}, Dim: Volume, Num: 0.00454609}, nil)
+)
+
+// Area; the base unit is the square metre. A prefix squares along with the metre
+// (a km² is 10^6 m²), so the prefixed units are listed one by one.
+var (
+	SquareMetre = Default.mustAdd(&Unit{Symbol: "m²", AltSymbols: []string{"m2", "m^2"}, Name: "square metre",
+		Aliases: []string{"square meter", "square metres", "square meters", "sq m"}, Dim: Area, Num: 1}, nil)
+	SquareKilometre = Default.mustAdd(&Unit{Symbol: "km²", AltSymbols: []string{"km2", "km^2"}, Name: "square kilometre",
+		Aliases: []string{"square kilometer", "square kilometres", "square kilometers", "sq km"}, Dim: Area, Num: 1e6}, nil)
+	SquareCentimetre = Default.mustAdd(&Unit{Symbol: "cm²", AltSymbols: []string{"cm2", "cm^2"}, Name: "square centimetre",
+		Aliases: []string{"square centimeter", "square centimetres", "square centimeters", "sq cm"}, Dim: Area, Num: 1, Den: 1e4}, nil)
+	Hectare = Default.mustAdd(&Unit{Symbol: "ha", Name: "hectare", Aliases: []string{"hectares"},
+		Dim: Area, Num: 1e4}, nil)
+	SquareInch = Default.mustAdd(&Unit{Symbol: "in²", AltSymbols: []string{"in2", "in^2"}, Name: "square inch",
+		Aliases: []string{"square inches", "sq in"}, Dim: Area, Num: 0.00064516}, nil)
+	SquareFoot = Default.mustAdd(&Unit{Symbol: "ft²", AltSymbols: []string{"ft2", "ft^2"}, Name: "square foot",
+		Aliases: []string{"square feet", "sq ft"}, Dim: Area, Num: 0.09290304}, nil)
+	Acre = Default.mustAdd(&Unit{Symbol: "ac", Name: "acre", Aliases: []string{"acres"},
+		Dim: Area, Num: 4046.8564224}, nil)
+	SquareMile = Default.mustAdd(&Unit{Symbol: "mi²", AltSymbols: []string{"mi2", "mi^2"}, Name: "square mile",
+		Aliases: []string{"square miles", "sq mi"}, Dim: Area, Num: 2589988.110336}, nil)
+)
+
+// Speed; the base unit is the metre per second. Per-hour units keep the 3600 in
+// their denominator.
+var (
+	MetrePerSecond = Default.mustAdd(&Unit{Symbol: "m/s", AltSymbols: []string{"mps"}, Name: "metre per second",
+		Aliases: []string{"meter per second", "metres per second", "meters per second"}, Dim: Speed, Num: 1}, nil)
+	KilometrePerHour = Default.mustAdd(&Unit{Symbol: "km/h", AltSymbols: []string{"kph", "kmh", "km/hr"},
+		Name: "kilometre per hour", Aliases: []string{"kilometer per hour", "kilometres per hour", "kilometers per hour"},
+		Dim: Speed, Num: 1000, Den: 3600}, nil)
+	MilePerHour = Default.mustAdd(&Unit{Symbol: "mph", AltSymbols: []string{"mi/h"}, Name: "mile per hour",
+		Aliases: []string{"miles per hour"}, Dim: Speed, Num: 1609.344, Den: 3600}, nil)
+	Knot = Default.mustAdd(&Unit{Symbol: "kn", AltSymbols: []string{"kt"}, Name: "knot", Aliases: []string{"knots"},
+		Dim: Speed, Num: 1852, Den: 3600}, nil)
+	FootPerSecond = Default.mustAdd(&Unit{Symbol: "ft/s", AltSymbols: []string{"fps"}, Name: "foot per second",
+		Aliases: []string{"feet per second"}, Dim: Speed, Num: 0.3048}, nil)
+)
+
+// Pressure; the base unit is the pascal.
+var (
+	Pascal = Default.mustAdd(&Unit{Symbol: "Pa", Name: "pascal", Aliases: []string{"pascals"},
+		Dim: Pressure, Num: 1}, SI)
+	Hectopascal = Default.mustLookup("hPa")
+	Kilopascal  = Default.mustLookup("kPa")
+	Bar         = Default.mustAdd(&Unit{Symbol: "bar", Name: "bar", Aliases: []string{"bars"},
+		Dim: Pressure, Num: 1e5}, SI)
+	Millibar = Default.mustLookup("mbar")
+	// The psi is a pound-force (0.45359237 kg × 9.80665 m/s²) per square inch.
+	PSI = Default.mustAdd(&Unit{Symbol: "psi", Name: "pound per square inch",
+		Aliases: []string{"pounds per square inch", "lbf/in²"}, Dim: Pressure, Num: 0.45359237 * 9.80665, Den: 0.00064516}, nil)
+	Atmosphere = Default.mustAdd(&Unit{Symbol: "atm", Name: "atmosphere", Aliases: []string{"atmospheres"},
+		Dim: Pressure, Num: 101325}, nil)
+	Torr = Default.mustAdd(&Unit{Symbol: "Torr", Name: "torr", Aliases: []string{"mmHg"},
+		Dim: Pressure, Num: 101325, Den: 760}, nil)
+)
+
+// Energy; the base unit is the joule. The calorie is the thermochemical one.
+var (
+	Joule = Default.mustAdd(&Unit{Symbol: "J", Name: "joule", Aliases: []string{"joules"},
+		Dim: Energy, Num: 1}, SI)
+	Kilojoule = Default.mustLookup("kJ")
+	Calorie   = Default.mustAdd(&Unit{Symbol: "cal", Name: "calorie", Aliases: []string{"calories"},
+		Dim: Energy, Num: 4.184}, SI)
+	Kilocalorie = Default.mustLookup("kcal")
+	WattHour    = Default.mustAdd(&Unit{Symbol: "Wh", Name: "watt-hour",
+		Aliases: []string{"watt-hours", "watt hour", "watt hours"}, Dim: Energy, Num: 3600}, SI)
+	KilowattHour = Default.mustLookup("kWh")
+	BTU          = Default.mustAdd(&Unit{Symbol: "BTU", AltSymbols: []string{"Btu"}, Name: "British thermal unit",
+		Aliases: []string{"British thermal units"}, Dim: Energy, Num: 1055.05585262}, nil)
+)
+
+// Data size; the base unit is the byte. Both the decimal SI multiples (1 kB is 1000
+// bytes) and the binary IEC ones (1 KiB is 1024 bytes, the 'KB' of exercise 3.13)
+// are defined. "KB" on its own is left out, since it is used for both.
+var (
+	Byte = Default.mustAdd(&Unit{Symbol: "B", Name: "byte", Aliases: []string{"bytes", "octet", "octets"},
+		Dim: Data, Num: 1}, append(multiples(SI), IEC...))
+	Kilobyte = Default.mustLookup("kB")
+	Megabyte = Default.mustLookup("MB")
+	Gigabyte = Default.mustLookup("GB")
+	Kibibyte = Default.mustLookup("KiB")
+	Mebibyte = Default.mustLookup("MiB")
+	Gibibyte = Default.mustLookup("GiB")
+	Bit      = Default.mustAdd(&Unit{Symbol: "bit", Name: "bit", Aliases: []string{"bits"},
+		Dim: Data, Num: 1, Den: 8}, append(multiples(SI), IEC...))
+)
+
+// Time, frequency, acceleration, force and power, the dimensions that compound
+// expressions such as '5 kWh / 2 h in W' or '9.81 kg*m/s^2 in N' need.
+var (
+	Second = Default.mustAdd(&Unit{Symbol: "s", AltSymbols: []string{"sec"}, Name: "second",
+		Aliases: []string{"seconds", "secs"}, Dim: Time, Num: 1}, SI)
+	Millisecond = Default.mustLookup("ms")
+	Minute      = Default.mustAdd(&Unit{Symbol: "min", Name: "minute", Aliases: []string{"minutes", "mins"},
+		Dim: Time, Num: 60}, nil)
+	Hour = Default.mustAdd(&Unit{Symbol: "h", AltSymbols: []string{"hr"}, Name: "hour",
+		Aliases: []string{"hours", "hrs"}, Dim: Time, Num: 3600}, nil)
+	Day = Default.mustAdd(&Unit{Symbol: "d", Name: "day", Aliases: []string{"days"},
+		Dim: Time, Num: 86400}, nil)
+	Week = Default.mustAdd(&Unit{Symbol: "wk", Name: "week", Aliases: []string{"weeks"},
+		Dim: Time, Num: 604800}, nil)
+
+	Hertz = Default.mustAdd(&Unit{Symbol: "Hz", Name: "hertz", Dim: Frequency, Num: 1}, SI)
+
+	MetrePerSecondSquared = Default.mustAdd(&Unit{Symbol: "m/s²", AltSymbols: []string{"m/s^2", "m/s2"},
+		Name: "metre per second squared", Aliases: []string{"meter per second squared",
+			"metres per second squared", "meters per second squared"}, Dim: Acceleration, Num: 1}, nil)
+
+	Newton = Default.mustAdd(&Unit{Symbol: "N", Name: "newton", Aliases: []string{"newtons"},
+		Dim: Force, Num: 1}, SI)
+	KilogramForce = Default.mustAdd(&Unit{Symbol: "kgf", Name: "kilogram-force",
+		Aliases: []string{"kilograms-force", "kilopond"}, Dim: Force, Num: 9.80665}, nil)
+	PoundForce = Default.mustAdd(&Unit{Symbol: "lbf", Name: "pound-force",
+		Aliases: []string{"pounds-force"}, Dim: Force, Num: 0.45359237 * 9.80665}, nil)
+
+	Watt = Default.mustAdd(&Unit{Symbol: "W", Name: "watt", Aliases: []string{"watts"},
+		Dim: Power, Num: 1}, SI)
+	Kilowatt = Default.mustLookup("kW")
+	// The mechanical horsepower is 550 foot-pounds-force per second.
+	Horsepower = Default.mustAdd(&Unit{Symbol: "hp", Name: "horsepower", Dim: Power,
+		Num: 550 * 0.3048 * 0.45359237 * 9.80665}, nil)
+)
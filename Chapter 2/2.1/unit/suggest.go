@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSuggestions is how many names 'Parse' suggests for an unknown unit.
+const maxSuggestions = 3
+
+// Parse looks up a unit like 'Lookup' does, but returns an '*UnknownUnitError'
+// with the closest known names when there is no such unit.
+func (r *Registry) Parse(s string) (*Unit, error) {
+	if u, ok := r.Lookup(s); ok {
+		return u, nil
+	}
+	return nil, &UnknownUnitError{Name: s, Suggestions: r.Suggest(s)}
+}
+
+// Suggest returns the known symbols, names and aliases closest to 's', for
+// "did you mean" messages. Closeness is the edit distance between lower-cased
+// strings, and only names within a third of the length of 's' (at least one edit)
+// are suggested, the closest first. Among equally close names, those starting with
+// the same letter as 's' come first, since typos rarely hit the first letter.
+func (r *Registry) Suggest(s string) []string {
+	low := strings.ToLower(s)
+	limit := utf8.RuneCountInString(s) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	type candidate struct {
+		name      string
+		dist      int
+		sameFirst bool
+	}
+	first, _ := utf8.DecodeRuneInString(low)
+	var cands []candidate
+	for name := range r.byName {
+		lname := strings.ToLower(name)
+		if d := editDistance(low, lname); d <= limit {
+			f, _ := utf8.DecodeRuneInString(lname)
+			cands = append(cands, candidate{name, d, f == first})
+		}
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		a, b := cands[i], cands[j]
+		if a.dist != b.dist {
+			return a.dist < b.dist
+		}
+		if a.sameFirst != b.sameFirst {
+			return a.sameFirst
+		}
+		return a.name < b.name
+	})
+	var names []string
+	for _, c := range cands {
+		if len(names) == maxSuggestions {
+			break
+		}
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// editDistance is the Levenshtein distance between two strings, counted in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// quoteList formats names as "a", "a" or "b", or "a", "b" or "c".
+func quoteList(names []string) string {
+	q := make([]string, len(names))
+	for i, n := range names {
+		q[i] = fmt.Sprintf("%q", n)
+	}
+	if len(q) == 1 {
+		return q[0]
+	}
+	return strings.Join(q[:len(q)-1], ", ") + " or " + q[len(q)-1]
+}
@@ -0,0 +1,110 @@
+// Package unit converts quantities between units of the same dimension. Every unit
+// is defined relative to the base unit of its dimension (the kelvin, the metre, the
+// kilogram), so a conversion goes from one unit to the base and from the base to the
+// other, and a new unit only needs its relation to the base. Units are kept in a
+// 'Registry' that looks them up by symbol, name or alias; 'Default' holds the
+// standard ones. SI prefixes ('k', 'm', 'µ', ...) can be applied to any unit whose
+// zero is the base unit's zero. Temperatures such as the degree Celsius are affine:
+// besides a factor they have an offset, and are never prefixed.
+
+package unit
+
+import (
+	"fmt"
+	"math"
+)
+
+// Dimension is the kind of quantity a unit measures. Only units of the same
+// dimension can be converted into one another.
+type Dimension string
+
+const (
+	Temperature Dimension = "temperature"
+	Length      Dimension = "length"
+	Mass        Dimension = "mass"
+	Volume      Dimension = "volume"
+	Area        Dimension = "area"
+	Speed       Dimension = "speed"
+	Pressure    Dimension = "pressure"
+	Energy      Dimension = "energy"
+	Data        Dimension = "data size"
+
+	Dimensionless Dimension = "dimensionless"
+	Time          Dimension = "time"
+	Acceleration  Dimension = "acceleration"
+	Frequency     Dimension = "frequency"
+	Force         Dimension = "force"
+	Power         Dimension = "power"
+)
+
+// Unit is a unit of measurement. A value 'v' in the unit amounts to
+//
+//	(v + Offset) * Num / Den
+//
+// base units of its dimension. The factor is kept as a fraction so that units such
+// as the degree Fahrenheit (5/9 of a kelvin) and prefixes such as milli (1/1000)
+// do not start out with a rounding error; a zero 'Den' counts as 1.
+type Unit struct {
+	Symbol     string   // the canonical symbol, e.g. "ft"
+	AltSymbols []string // other symbols, which take prefixes like 'Symbol', e.g. "l"
+	Name       string   // the singular name, e.g. "foot"
+	Aliases    []string // other names and spellings, e.g. "feet"
+	Dim        Dimension
+	Num        float64
+	Den        float64
+	Offset     float64 // in the unit itself; non-zero only for affine units
+
+	vec *Dims // the vector of a unit built from an expression; see 'Dims'
+}
+
+func (u *Unit) String() string { return u.Symbol }
+
+// Affine reports whether the unit has an offset, i.e. whether its zero differs from
+// the zero of the base unit.
+func (u *Unit) Affine() bool { return u.Offset != 0 }
+
+func (u *Unit) den() float64 {
+	if u.Den == 0 {
+		return 1
+	}
+	return u.Den
+}
+
+// Dims returns the dimension vector of the unit. It reports false for units of a
+// dimension that has none; see 'Dimension.Dims'.
+func (u *Unit) Dims() (Dims, bool) {
+	if u.vec != nil {
+		return *u.vec, true
+	}
+	return u.Dim.Dims()
+}
+
+// ToBase converts 'v' from the unit to the base unit of its dimension.
+func (u *Unit) ToBase(v float64) float64 { return (v + u.Offset) * u.Num / u.den() }
+
+// FromBase converts 'b' from the base unit of the dimension to the unit.
+func (u *Unit) FromBase(b float64) float64 { return b*u.den()/u.Num - u.Offset }
+
+// DimensionError is returned when converting between units of different dimensions.
+type DimensionError struct {
+	From, To *Unit
+}
+
+func (e *DimensionError) Error() string {
+	return fmt.Sprintf("cannot convert %s (%s) to %s (%s)", e.From.Symbol, e.From.Dim, e.To.Symbol, e.To.Dim)
+}
+
+// Convert converts 'v' from unit 'from' to unit 'to'. Instead of going through the
+// base unit in two steps, both factors and offsets are combined into one expression
+// with a single division at the end, which keeps round numbers round: 100°C is
+// exactly 212°F and 273.15 K exactly 0°C.
+func Convert(v float64, from, to *Unit) (float64, error) {
+	if from.Dim != to.Dim {
+		return math.NaN(), &DimensionError{from, to}
+	}
+	if from == to {
+		return v, nil
+	}
+	num := (v+from.Offset)*from.Num*to.den() - to.Offset*from.den()*to.Num
+	return num / (from.den() * to.Num), nil
+}
@@ -0,0 +1,229 @@
+// These tests check conversions between the standard units, including the affine
+// temperature scales and SI-prefixed units, as well as the registry's lookups and
+// errors. To run them, type 'go test ./...' in the '2.1' directory.
+
+package unit
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		v        float64
+		from, to *Unit
+		want     float64
+	}{
+		{100, Celsius, Fahrenheit, 212},
+		{-40, Celsius, Fahrenheit, -40},
+		{32, Fahrenheit, Celsius, 0},
+		{0, Kelvin, Fahrenheit, -459.67},
+		{273.15, Kelvin, Celsius, 0},
+		{0, Kelvin, Rankine, 0},
+		{671.67, Rankine, Fahrenheit, 212},
+		{100, Celsius, Reaumur, 80},
+		{0, Delisle, Celsius, 100},
+		{150, Delisle, Celsius, 0},
+		{0, Kelvin, Delisle, 559.725},
+		{33, DegreeNewton, Celsius, 100},
+		{60, Romer, Celsius, 100},
+		{7.5, Romer, Celsius, 0},
+		{1, Foot, Metre, 0.3048},
+		{1, Mile, Kilometre, 1.609344},
+		{12, Inch, Foot, 1},
+		{3, Foot, Yard, 1},
+		{25.4, Millimetre, Inch, 1},
+		{1, Pound, Kilogram, 0.45359237},
+		{16, Ounce, Pound, 1},
+		{1, Tonne, Kilogram, 1000},
+		{1500, Milligram, Gram, 1.5},
+		{14, Pound, Stone, 1},
+		{1, USGallon, Litre, 3.785411784},
+		{16, USCup, USGallon, 1},
+		{8, USFluidOunce, USCup, 1},
+		{1, ImperialGallon, Millilitre, 4546.09},
+		{1, CubicCentimetre, Millilitre, 1},
+		{1, Hectare, SquareMetre, 10000},
+		{640, Acre, SquareMile, 1},
+		{144, SquareInch, SquareFoot, 1},
+		{1, SquareKilometre, Hectare, 100},
+		{36, KilometrePerHour, MetrePerSecond, 10},
+		{60, MilePerHour, KilometrePerHour, 96.56064},
+		{1, Knot, KilometrePerHour, 1.852},
+		{1, Atmosphere, Hectopascal, 1013.25},
+		{1, Bar, Kilopascal, 100},
+		{1, PSI, Pascal, 6894.757293168361},
+		{760, Torr, Atmosphere, 1},
+		{1, Kilocalorie, Kilojoule, 4.184},
+		{1, KilowattHour, Joule, 3.6e6},
+		{1, BTU, Joule, 1055.05585262},
+		{1, Kibibyte, Byte, 1024},
+		{1, Gibibyte, Megabyte, 1073.741824},
+		{1, Byte, Bit, 8},
+		{1, Default.mustLookup("YiB"), Byte, 1208925819614629174706176},
+	}
+	for _, test := range tests {
+		got, err := Convert(test.v, test.from, test.to)
+		if err != nil {
+			t.Errorf("Convert(%v, %s, %s): %v", test.v, test.from, test.to, err)
+			continue
+		}
+		if math.Abs(got-test.want) > 1e-12*math.Max(1, math.Abs(test.want)) {
+			t.Errorf("Convert(%v, %s, %s) = %v, expected %v", test.v, test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestConvertDimensionMismatch(t *testing.T) {
+	_, err := Convert(1, Metre, Kilogram)
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) {
+		t.Fatalf("Convert(1, m, kg) error = %v, expected a *DimensionError", err)
+	}
+	if got, want := err.Error(), "cannot convert m (length) to kg (mass)"; got != want {
+		t.Errorf("error = %q, expected %q", got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		want *Unit
+	}{
+		{"ft", Foot},
+		{"feet", Foot},
+		{"FEET", Foot},
+		{"°C", Celsius},
+		{"C", Celsius},
+		{"celsius", Celsius},
+		{"km", Kilometre},
+		{"kilometers", Kilometre},
+		{"kg", Kilogram},
+		{"kilogram", Kilogram},
+		{"µg", Default.mustLookup("ug")},
+	}
+	for _, test := range tests {
+		if u, ok := Default.Lookup(test.name); !ok || u != test.want {
+			t.Errorf("Lookup(%q) = %v, %v, expected %v", test.name, u, ok, test.want)
+		}
+	}
+	// Symbols are case-sensitive: 'Mm' is a megametre, not a millimetre.
+	if u, _ := Default.Lookup("Mm"); u == Millimetre {
+		t.Errorf("Lookup(Mm) = mm")
+	}
+	if _, ok := Default.Lookup("parsec"); ok {
+		t.Errorf("Lookup(parsec) succeeded")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if err := r.AddPrefixed(Celsius, SI); err == nil {
+		t.Errorf("prefixing an affine unit succeeded")
+	}
+	if err := r.Add(&Unit{Symbol: "x", Dim: Length}); err == nil {
+		t.Errorf("adding a unit without a factor succeeded")
+	}
+	if err := r.Add(&Unit{Symbol: "m", Name: "metre", Dim: Length, Num: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(&Unit{Symbol: "m", Name: "minute", Dim: "time", Num: 60}); err == nil {
+		t.Errorf("adding a clashing symbol succeeded")
+	}
+
+	_, err := r.Convert(1, "m", "furlong")
+	var unknown *UnknownUnitError
+	if !errors.As(err, &unknown) || unknown.Name != "furlong" {
+		t.Errorf("Convert to an unknown unit: error = %v, expected an *UnknownUnitError", err)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	for _, p := range SI {
+		u := p.Apply(Metre)
+		got := u.ToBase(1)
+		want := math.Pow10(p.Exp)
+		if math.Abs(got-want) > 1e-15*want {
+			t.Errorf("1 %s = %v m, expected %v", u.Symbol, got, want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	_, err := Default.Parse("celcius")
+	if got, want := err.Error(), `unknown unit "celcius"; did you mean "celsius"?`; got != want {
+		t.Errorf("Parse(celcius) error = %q, expected %q", got, want)
+	}
+	if got := Default.Suggest("xyzzy"); len(got) != 0 {
+		t.Errorf("Suggest(xyzzy) = %q, expected nothing", got)
+	}
+}
+
+// TestRoundTrip converts a few values from every unit of every dimension to every
+// other unit of the same dimension and back. Each conversion rounds to a float64,
+// so the round trip may be off by a few ulps of the larger of the start value and
+// the intermediate one (offsets included), measured in the start unit: 1 µK is
+// -273.149999 °C, and the digits of the microkelvin are lost next to the 273.
+func TestRoundTrip(t *testing.T) {
+	values := []float64{0, 1, -17.5, 0.001, 123456.789}
+	dims := []Dimension{Temperature, Length, Mass, Volume, Area, Speed, Pressure, Energy, Data,
+		Time, Frequency, Acceleration, Force, Power}
+	if got := Default.Dimensions(); !reflect.DeepEqual(got, dims) {
+		t.Errorf("Dimensions() = %q, expected %q", got, dims)
+	}
+	for _, d := range dims {
+		units := Default.Units(d)
+		for _, a := range units {
+			for _, b := range units {
+				for _, v := range values {
+					there, err := Convert(v, a, b)
+					if err != nil {
+						t.Fatal(err)
+					}
+					back, err := Convert(there, b, a)
+					if err != nil {
+						t.Fatal(err)
+					}
+					tol := 1e-12 * (math.Abs(v) + math.Abs(a.Offset) +
+						(math.Abs(there)+math.Abs(b.Offset))*scale(b)/scale(a))
+					if math.Abs(back-v) > tol {
+						t.Errorf("%s: %v %s -> %v %s -> %v %s", d, v, a, there, b, back, a)
+					}
+				}
+			}
+		}
+	}
+}
+
+// scale is the size of a unit in base units; the Delisle degree counts down.
+func scale(u *Unit) float64 { return math.Abs(u.Num / u.den()) }
+
+func TestExactFactor(t *testing.T) {
+	pow := func(base, exp int64) *big.Rat {
+		return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(base), big.NewInt(exp), nil))
+	}
+	tests := []struct {
+		f    float64
+		want *big.Rat
+	}{
+		{0.3048, big.NewRat(3048, 10000)},
+		{273.15, big.NewRat(27315, 100)},
+		{1e24, pow(10, 24)},
+		{IEC[6].Factor, pow(2, 70)},
+		{IEC[7].Factor, pow(2, 80)},
+	}
+	for _, test := range tests {
+		if got := ExactFactor(test.f); got.Cmp(test.want) != 0 {
+			t.Errorf("ExactFactor(%v) = %s, expected %s", test.f, got.RatString(), test.want.RatString())
+		}
+	}
+
+	got, err := ConvertExact(big.NewRat(1, 1), Default.mustLookup("YiB"), Default.mustLookup("YB"))
+	if want := new(big.Rat).Quo(pow(2, 80), pow(10, 24)); err != nil || got.Cmp(want) != 0 {
+		t.Errorf("1 YiB = %s YB, %v; expected %s", got.RatString(), err, want.RatString())
+	}
+}
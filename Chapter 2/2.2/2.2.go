@@ -1,14 +1,21 @@
-// Code that supports conversions for temperature, length and weight.
+// Code that supports conversions for temperature, length, weight, volume, area, speed,
+// pressure, energy and data size. Inputs carry their unit, as in '12.5ft', '100 °F'
+// or '72F to C'; see parse.go. Units can be multiplied and divided, and whole lines
+// such as '5 kWh / 2 h in W' are evaluated as expressions with their dimensions
+// checked, which turns the converter into a small calculator.
 
 package main
 
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"math/big"
 	"os"
-	"strconv"
+	"strings"
 
-	"gopl.io/ch2/tempconv"
+	tempconv "GoBookSolutions/2.1"
+	"GoBookSolutions/2.1/unit"
 )
 
 func main() {
@@ -19,17 +26,20 @@ func main() {
 	}
 }
 
+// convertArgs treats the command line as one line of input, since a shell splits
+// '72F to C' into three arguments.
 func convertArgs(args []string) {
+	var words []string
 	for _, arg := range args {
-		convertAndPrint(arg)
+		words = append(words, strings.Fields(arg)...)
 	}
+	convertAndPrint(os.Stdout, os.Stderr, words)
 }
 
 func convertStdin() {
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
-		arg := scanner.Text()
-		convertAndPrint(arg)
+		convertAndPrint(os.Stdout, os.Stderr, strings.Fields(scanner.Text()))
 	}
 	if err := scanner.Err(); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to read input: %v\n", err)
@@ -37,54 +47,115 @@ func convertStdin() {
 	}
 }
 
-func convertAndPrint(arg string) {
-	value, err := strconv.ParseFloat(arg, 64)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "invalid input: %s\n", arg)
+func convertAndPrint(w, errw io.Writer, words []string) {
+	if isExpression(words) {
+		q, to, lhs, err := parseExpression(words)
+		if err != nil {
+			fmt.Fprintln(errw, err)
+			return
+		}
+		if to == nil {
+			to = q.Unit
+		}
+		fmt.Fprintf(w, "%s = %s\n", lhs, format(conv(q.Value, q.Unit, to), to))
 		return
 	}
+	for len(words) > 0 {
+		q, rest, err := parseQuery(words)
+		words = rest
+		if err != nil {
+			fmt.Fprintln(errw, err)
+			words = skipToNumber(words)
+			continue
+		}
+		switch {
+		case q.from == nil:
+			printAll(w, q.value)
+		case q.to != nil:
+			fmt.Fprintf(w, "%s = %s\n", format(q.value, q.from), format(conv(q.value, q.from, q.to), q.to))
+		case targets[q.from.Dim] == nil:
+			d, _ := q.from.Dims()
+			to := unit.Default.BaseUnit(d)
+			fmt.Fprintf(w, "%s = %s\n\n", format(q.value, q.from), format(conv(q.value, q.from, to), to))
+		default:
+			for _, to := range targets[q.from.Dim] {
+				if to != q.from {
+					fmt.Fprintf(w, "%s = %s\n", format(q.value, q.from), format(conv(q.value, q.from, to), to))
+				}
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
 
+// printAll prints every conversion for a bare number, whose unit is unknown.
+func printAll(w io.Writer, value float64) {
 	// Temperature conversion
 	c := tempconv.Celsius(value)
 	f := tempconv.Fahrenheit(value)
-	fmt.Printf("%.2f°C = %.2f°F\n", c, tempconv.CToF(c))
-	fmt.Printf("%.2f°F = %.2f°C\n", f, tempconv.FToC(f))
+	fmt.Fprintf(w, "%.2f°C = %.2f°F\n", c, tempconv.CToF(c))
+	fmt.Fprintf(w, "%.2f°F = %.2f°C\n", f, tempconv.FToC(f))
 
 	// Length conversion
 	m := Meter(value)
 	ft := Foot(value)
-	fmt.Printf("%.2fm = %.2fft\n", m, MToFt(m))
-	fmt.Printf("%.2fft = %.2fm\n", ft, FtToM(ft))
+	fmt.Fprintf(w, "%.2fm = %.2fft\n", m, MToFt(m))
+	fmt.Fprintf(w, "%.2fft = %.2fm\n", ft, FtToM(ft))
 
 	// Weight conversion
 	kg := Kilogram(value)
 	lb := Pound(value)
-	fmt.Printf("%.2fkg = %.2flb\n", kg, KGToLb(kg))
-	fmt.Printf("%.2flb = %.2fkg\n", lb, LbToKG(lb))
+	fmt.Fprintf(w, "%.2fkg = %.2flb\n", kg, KGToLb(kg))
+	fmt.Fprintf(w, "%.2flb = %.2fkg\n", lb, LbToKG(lb))
 
-	fmt.Println() // Terminal prints an empty line between conversions
+	fmt.Fprintln(w) // Terminal prints an empty line between conversions
 }
 
+/* The length and weight types below used to carry their own magic factors (3.28084
+feet per metre, 2.20462 pounds per kilogram). They now go through the 'unit'
+package, which uses the exact definitions of the foot (0.3048 m) and the pound
+(0.45359237 kg), the same way 'tempconv' does for temperatures. */
+
 // Length conversion functions
 type Meter float64
 type Foot float64
 
-func MToFt(m Meter) Foot {
-	return Foot(m * 3.28084)
-}
+func MToFt(m Meter) Foot { return Foot(conv(float64(m), unit.Metre, unit.Foot)) }
 
-func FtToM(ft Foot) Meter {
-	return Meter(ft / 3.28084)
-}
+func FtToM(ft Foot) Meter { return Meter(conv(float64(ft), unit.Foot, unit.Metre)) }
 
 // Weight conversion functions
 type Kilogram float64
 type Pound float64
 
-func KGToLb(kg Kilogram) Pound {
-	return Pound(kg * 2.20462)
+func KGToLb(kg Kilogram) Pound { return Pound(conv(float64(kg), unit.Kilogram, unit.Pound)) }
+
+func LbToKG(lb Pound) Kilogram { return Kilogram(conv(float64(lb), unit.Pound, unit.Kilogram)) }
+
+// Exact versions of the length and weight conversions, on 'big.Rat' values, like
+// those of 'tempconv'. A foot is exactly 3048/10000 m and a pound exactly
+// 45359237/100000000 kg, so these round-trip without any error.
+func MToFtExact(m *big.Rat) *big.Rat { return convExact(m, unit.Metre, unit.Foot) }
+
+func FtToMExact(ft *big.Rat) *big.Rat { return convExact(ft, unit.Foot, unit.Metre) }
+
+func KGToLbExact(kg *big.Rat) *big.Rat { return convExact(kg, unit.Kilogram, unit.Pound) }
+
+func LbToKGExact(lb *big.Rat) *big.Rat { return convExact(lb, unit.Pound, unit.Kilogram) }
+
+// conv cannot fail, since both units of each pair have the same dimension.
+func conv(v float64, from, to *unit.Unit) float64 {
+	r, err := unit.Convert(v, from, to)
+	if err != nil {
+		panic(err)
+	}
+	return r
 }
 
-func LbToKG(lb Pound) Kilogram {
-	return Kilogram(lb / 2.20462)
+func convExact(v *big.Rat, from, to *unit.Unit) *big.Rat {
+	r, err := unit.ConvertExact(v, from, to)
+	if err != nil {
+		panic(err)
+	}
+	return r
 }
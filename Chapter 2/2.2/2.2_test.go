@@ -0,0 +1,71 @@
+// These tests run inputs through 'convertAndPrint' and compare what it prints, to
+// check that units are read from every supported spelling and that mistakes are
+// reported without stopping the other conversions. Run them with 'go test'.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvertAndPrint(t *testing.T) {
+	tests := []struct {
+		input    string
+		out, err string
+	}{
+		{"72F to C", "72°F = 22.2222°C\n", ""},
+		{"100 °F to K", "100°F = 310.928 K\n", ""},
+		{"12.5ft in m", "12.5 ft = 3.81 m\n", ""},
+		{"5 in to cm", "5 in = 12.7 cm\n", ""},
+		{"2 metric ton to kg", "2 t = 2000 kg\n", ""},
+		{"1 degrees Celsius to degrees Fahrenheit", "1°C = 33.8°F\n", ""},
+		{"-40C", "-40°C = -40°F\n-40°C = 233.15 K\n\n", ""},
+		{"3 lb", "3 lb = 1.36078 kg\n3 lb = 1360.78 g\n3 lb = 0.00136078 t\n" +
+			"3 lb = 48 oz\n3 lb = 0.214286 st\n\n", ""},
+		{"10 fet 1kg to lb", "1 kg = 2.20462 lb\n",
+			"unknown unit \"fet\"; did you mean \"feet\" or \"ft\"?\n"},
+		{"3 ft to kg", "", "cannot convert ft (length) to kg (mass)\n"},
+		{"7 to C", "", "7 has no unit to convert from\n"},
+		{"abc", "", "invalid input: abc\n"},
+		{"60 mph in m/s", "60 mph = 26.8224 m/s\n", ""},
+		{"9.81 kg*m/s^2 in N", "9.81 kg*m/s^2 = 9.81 N\n", ""},
+		{"1 J/s to W", "1 J/s = 1 W\n", ""},
+		{"3 N", "3 N = 0.305915 kgf\n3 N = 0.674427 lbf\n\n", ""},
+		{"2 N/m", "2 N/m = 2 kg/s²\n\n", ""},
+		{"5 kWh / 2 h in W", "5 kWh / 2 h = 2500 W\n", ""},
+		{"5 kWh / 2 h", "5 kWh / 2 h = 2500 W\n", ""},
+		{"( 2 m ) ^ 3 in L", "( 2 m ) ^ 3 = 8000 L\n", ""},
+		{"5 in * 2 in to cm^2", "5 in * 2 in = 64.516 cm^2\n", ""},
+		{"5 kWh / 2 h in J", "", "5 kWh / 2 h: cannot convert W (power) to J (energy)\n"},
+		{"3 °C * 2", "", "3 °C * 2: °C is an affine unit and can only be converted on its own\n"},
+		{"1,250 ft to m", "1250 ft = 381 m\n", ""},
+		{"1,250.5kg to t", "1250.5 kg = 1.2505 t\n", ""},
+		{"1,25 ft to m", "", "unknown unit \",25\"\n"},
+		{"1e400 m", "", "invalid input: 1e400\n"},
+		{"-1e400m to ft", "", "invalid input: -1e400m\n"},
+		{"5 m^200 in ft", "", "m^200: dimension exponent out of range: ^200\n"},
+		{"5 m^100 * m^100", "", "5 m^100 * m^100: dimension exponent out of range\n"},
+		{"1 m / 0 s", "", "1 m / 0 s: division by zero\n"},
+		{"1 m / 0 s in km/h", "", "1 m / 0 s: division by zero\n"},
+	}
+	for _, test := range tests {
+		var out, errw bytes.Buffer
+		convertAndPrint(&out, &errw, strings.Fields(test.input))
+		if out.String() != test.out || errw.String() != test.err {
+			t.Errorf("%q: printed %q and %q, expected %q and %q",
+				test.input, out.String(), errw.String(), test.out, test.err)
+		}
+	}
+}
+
+func TestBareNumber(t *testing.T) {
+	var out, errw bytes.Buffer
+	convertAndPrint(&out, &errw, []string{"100"})
+	want := "100.00°C = 212.00°F\n100.00°F = 37.78°C\n100.00m = 328.08ft\n" +
+		"100.00ft = 30.48m\n100.00kg = 220.46lb\n100.00lb = 45.36kg\n\n"
+	if out.String() != want {
+		t.Errorf("100: printed %q, expected %q", out.String(), want)
+	}
+}
@@ -0,0 +1,65 @@
+// Property-based tests for the length and weight conversions: random rationals must
+// survive a round trip exactly in exact mode, and random floats must come back
+// within a relative 1e-15 in float mode. Run them with 'go test'.
+
+package main
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+func randRat(values []reflect.Value, r *rand.Rand) {
+	for i := range values {
+		values[i] = reflect.ValueOf(big.NewRat(r.Int63n(1e18)-5e17, r.Int63n(1e6)+1))
+	}
+}
+
+func randFloat(values []reflect.Value, r *rand.Rand) {
+	for i := range values {
+		values[i] = reflect.ValueOf((r.Float64() - 0.5) * math.Pow(10, float64(r.Intn(12)-3)))
+	}
+}
+
+func TestExactRoundTrip(t *testing.T) {
+	trips := []struct {
+		name        string
+		there, back func(*big.Rat) *big.Rat
+	}{
+		{"m->ft->m", MToFtExact, FtToMExact},
+		{"ft->m->ft", FtToMExact, MToFtExact},
+		{"kg->lb->kg", KGToLbExact, LbToKGExact},
+		{"lb->kg->lb", LbToKGExact, KGToLbExact},
+	}
+	for _, trip := range trips {
+		prop := func(v *big.Rat) bool { return trip.back(trip.there(v)).Cmp(v) == 0 }
+		if err := quick.Check(prop, &quick.Config{MaxCount: 2000, Values: randRat}); err != nil {
+			t.Errorf("%s: %v", trip.name, err)
+		}
+	}
+	if got := FtToMExact(big.NewRat(1, 1)); got.Cmp(big.NewRat(3048, 10000)) != 0 {
+		t.Errorf("1 ft = %s m, expected 0.3048", got.RatString())
+	}
+}
+
+func TestFloatRoundTrip(t *testing.T) {
+	trips := []struct {
+		name string
+		trip func(float64) float64
+	}{
+		{"m->ft->m", func(v float64) float64 { return float64(FtToM(MToFt(Meter(v)))) }},
+		{"ft->m->ft", func(v float64) float64 { return float64(MToFt(FtToM(Foot(v)))) }},
+		{"kg->lb->kg", func(v float64) float64 { return float64(LbToKG(KGToLb(Kilogram(v)))) }},
+		{"lb->kg->lb", func(v float64) float64 { return float64(KGToLb(LbToKG(Pound(v)))) }},
+	}
+	for _, trip := range trips {
+		prop := func(v float64) bool { return math.Abs(trip.trip(v)-v) <= 1e-15*math.Abs(v) }
+		if err := quick.Check(prop, &quick.Config{MaxCount: 20000, Values: randFloat}); err != nil {
+			t.Errorf("%s: %v", trip.name, err)
+		}
+	}
+}
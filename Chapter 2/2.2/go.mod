@@ -2,4 +2,15 @@ module GoBookSolutions/2.2
 
 go 1.20
 
-require gopl.io v0.0.0-20211004154805-1ae3ec64947b // indirect
+require (
+	GoBookSolutions/2.1 v0.0.0
+	GoBookSolutions/3.11 v0.0.0
+)
+
+require GoBookSolutions/3.13 v0.0.0 // indirect
+
+replace (
+	GoBookSolutions/2.1 => ../2.1
+	GoBookSolutions/3.11 => "../../Chapter 3/3.11"
+	GoBookSolutions/3.13 => "../../Chapter 3/3.13"
+)
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"GoBookSolutions/2.1/unit"
+	"GoBookSolutions/3.11/numfmt"
+)
+
+/* Inputs name their unit, either glued to the number or as the next word: '12.5ft',
+'100 °F', '3 lb', '2 metric ton'. Units are looked up in 'unit.Default' by symbol,
+name or alias, trying the longest run of up to 'maxUnitWords' words first so that
+multi-word names work. A quantity can be followed by 'to' or 'in' and a target unit
+('72F to C'), in which case only that conversion is printed; otherwise it is
+converted to the usual units of its dimension (see 'targets'). Since 'in' is also the
+inch, '5 in' is five inches, and 'in' is only read as a keyword after a unit. A bare
+number keeps the old behaviour of printing every conversion. */
+
+const maxUnitWords = 3
+
+// number matches a number at the start of a word. The digits may be grouped by
+// thousands with commas, as people write them ('1,250 ft'); 'numfmt' checks that the
+// groups are right and reads the number.
+var number = regexp.MustCompile(`^[+-]?(?:\d{1,3}(?:,\d{3})+(?:\.\d*)?|\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?`)
+
+// query is one conversion request. A nil 'from' is a bare number and a nil 'to'
+// asks for the usual targets.
+type query struct {
+	value    float64
+	from, to *unit.Unit
+}
+
+// skipToNumber drops the words up to the next one starting with a number. After an
+// invalid query, it is where the next query starts, so that one typo does not hide
+// the other conversions.
+func skipToNumber(words []string) []string {
+	for len(words) > 0 && !number.MatchString(words[0]) {
+		words = words[1:]
+	}
+	return words
+}
+
+// parseQuery parses one query from the front of 'words' and returns the words left.
+func parseQuery(words []string) (query, []string, error) {
+	var q query
+	w := words[0]
+	loc := number.FindStringIndex(w)
+	if loc == nil {
+		return q, words[1:], fmt.Errorf("invalid input: %s", w)
+	}
+	r, err := numfmt.English.Parse(w[:loc[1]])
+	if err != nil {
+		return q, words[1:], fmt.Errorf("invalid input: %s", w)
+	}
+	// Beyond the range of a float64 the value is infinite, which 'strconv.ParseFloat'
+	// rejected with 'strconv.ErrRange'; so do we.
+	if q.value, _ = r.Float64(); math.IsInf(q.value, 0) {
+		return q, words[1:], fmt.Errorf("invalid input: %s", w)
+	}
+	rest := words[1:]
+
+	if suffix := w[loc[1]:]; suffix != "" {
+		// The unit is glued to the number, and may go on in the next words.
+		u, n, err := parseUnit(append([]string{suffix}, rest...))
+		if err != nil {
+			return q, rest, err
+		}
+		q.from, rest = u, rest[n-1:]
+	} else if len(rest) > 0 && !number.MatchString(rest[0]) && !isKeyword(rest) {
+		u, n, err := parseUnit(rest)
+		if err != nil {
+			return q, rest[1:], err
+		}
+		q.from, rest = u, rest[n:]
+	}
+
+	if isKeyword(rest) || q.from != nil && len(rest) > 1 && rest[0] == "in" {
+		if q.from == nil {
+			return q, rest[1:], fmt.Errorf("%s has no unit to convert from", w)
+		}
+		u, n, err := parseUnit(rest[1:])
+		if err != nil {
+			return q, rest[2:], err
+		}
+		q.to, rest = u, rest[1+n:]
+		if q.to.Dim != q.from.Dim {
+			return q, rest, &unit.DimensionError{From: q.from, To: q.to}
+		}
+	}
+	return q, rest, nil
+}
+
+// isKeyword reports whether 'words' starts with 'to' followed by a unit.
+func isKeyword(words []string) bool {
+	return len(words) > 1 && words[0] == "to"
+}
+
+// parseUnit finds the unit named by the longest run of up to 'maxUnitWords' words
+// at the front of 'words' and returns it with the number of words it took.
+func parseUnit(words []string) (*unit.Unit, int, error) {
+	for n := maxUnitWords; n > 1; n-- {
+		if n <= len(words) {
+			if u, ok := unit.Default.Lookup(strings.Join(words[:n], " ")); ok {
+				return u, n, nil
+			}
+		}
+	}
+	u, err := unit.Default.Parse(words[0])
+	if err != nil && strings.ContainsAny(words[0], "/*^·×²³0123456789") {
+		// Not a known unit, but maybe a compound one such as 'J/s' or 'kg*m/s^2'.
+		if v, perr := unit.Default.ParseExpr(words[0]); perr == nil {
+			u, err = v, nil
+		} else if errors.Is(perr, unit.ErrExponentRange) {
+			err = perr
+		}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return u, 1, nil
+}
+
+// isExpression reports whether a line uses arithmetic between words, as in
+// '5 kWh / 2 h in W', and must be read as a whole by 'unit.Registry.Eval' rather
+// than as a list of quantities.
+func isExpression(words []string) bool {
+	for _, w := range words {
+		switch {
+		case w == "*" || w == "/" || w == "×" || w == "·" || w == "^",
+			strings.ContainsAny(w, "()"):
+			return true
+		}
+	}
+	return false
+}
+
+// parseExpression splits an expression line at its last 'in' or 'to' that is
+// followed by a valid unit expression, evaluates the left side and parses the
+// right side. 'to' is nil if there is no target.
+func parseExpression(words []string) (q unit.Quantity, to *unit.Unit, lhs string, err error) {
+	lhs = strings.Join(words, " ")
+	for i := len(words) - 2; i > 0; i-- {
+		if words[i] != "in" && words[i] != "to" {
+			continue
+		}
+		if u, err := unit.Default.ParseExpr(strings.Join(words[i+1:], " ")); err == nil {
+			lhs, to = strings.Join(words[:i], " "), u
+			break
+		}
+	}
+	q, err = unit.Default.Eval(lhs)
+	if err == nil && to != nil && q.Unit.Dim != to.Dim {
+		err = fmt.Errorf("%s: %v", lhs, &unit.DimensionError{From: q.Unit, To: to})
+	}
+	return q, to, lhs, err
+}
+
+// targets lists, per dimension, the units a quantity is converted to when no
+// target is given. The registry holds every SI-prefixed unit, which would be far
+// too many to print. Quantities of other dimensions are printed in base units.
+var targets = map[unit.Dimension][]*unit.Unit{
+	unit.Temperature: {unit.Celsius, unit.Fahrenheit, unit.Kelvin},
+	unit.Length: {unit.Metre, unit.Kilometre, unit.Centimetre, unit.Millimetre,
+		unit.Inch, unit.Foot, unit.Yard, unit.Mile},
+	unit.Mass: {unit.Kilogram, unit.Gram, unit.Tonne, unit.Pound, unit.Ounce, unit.Stone},
+	unit.Volume: {unit.Litre, unit.Millilitre, unit.CubicMetre, unit.USGallon, unit.ImperialGallon,
+		unit.USCup, unit.USFluidOunce},
+	unit.Area: {unit.SquareMetre, unit.SquareKilometre, unit.Hectare, unit.SquareFoot,
+		unit.Acre, unit.SquareMile},
+	unit.Speed:    {unit.MetrePerSecond, unit.KilometrePerHour, unit.MilePerHour, unit.Knot},
+	unit.Pressure: {unit.Pascal, unit.Hectopascal, unit.Bar, unit.PSI, unit.Atmosphere},
+	unit.Energy:   {unit.Joule, unit.Kilojoule, unit.Kilocalorie, unit.KilowattHour, unit.BTU},
+	unit.Data: {unit.Byte, unit.Kilobyte, unit.Megabyte, unit.Gigabyte,
+		unit.Kibibyte, unit.Mebibyte, unit.Gibibyte},
+	unit.Time:  {unit.Second, unit.Minute, unit.Hour, unit.Day},
+	unit.Force: {unit.Newton, unit.KilogramForce, unit.PoundForce},
+	unit.Power: {unit.Watt, unit.Kilowatt, unit.Horsepower},
+}
+
+// format writes a quantity with up to six significant digits. Degree symbols are
+// written against the number, other symbols after a space.
+func format(v float64, u *unit.Unit) string {
+	s := strconv.FormatFloat(v, 'g', 6, 64)
+	if strings.HasPrefix(u.Symbol, "°") {
+		return s + u.Symbol
+	}
+	return s + " " + u.Symbol
+}
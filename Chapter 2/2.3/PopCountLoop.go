@@ -1,17 +1,11 @@
 package popcount
 
-var pcLoop [256]byte
+import "GoBookSolutions/popcount"
 
-func init() {
-	for i := range pc {
-		pcLoop[i] = pcLoop[i/2] + byte(i&1)
-	}
-}
+// 'PopCountLoop' used to fill a table of its own, 'pcLoop', in an 'init' that ranged over the
+// other table, 'pc'. Both have 256 entries, so it happened to work, but only by accident. The
+// shared package has one table for both.
 
 func PopCountLoop(x uint64) int {
-	count := 0
-	for i := 0; i < 8; i++ {
-		count += int(pcLoop[byte(x>>(i*8))])
-	}
-	return count
+	return popcount.PopCountLoop(x)
 }
@@ -1,3 +1,9 @@
 module GoBookSolutions/2.3
 
 go 1.20
+
+require GoBookSolutions/popcount v0.0.0
+
+require golang.org/x/sys v0.15.0 // indirect
+
+replace GoBookSolutions/popcount => ../popcount
@@ -1,13 +1,7 @@
 package popcount
 
-func PopCountShift(x uint64) int {
-	count := 0
+import "GoBookSolutions/popcount"
 
-	for i := 0; i < 64; i++ {
-		if x&1 == 1 {
-			count++
-		}
-		x >>= 1
-	}
-	return count
+func PopCountShift(x uint64) int {
+	return popcount.PopCountShift(x)
 }
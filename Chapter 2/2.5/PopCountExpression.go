@@ -1,20 +1,10 @@
 package popcount
 
-var pc [256]byte
+import "GoBookSolutions/popcount"
 
-func init() {
-	for i := range pc {
-		pc[i] = pc[i/2] + byte(i&1)
-	}
-}
+// The table and 'PopCount' now live in the shared 'popcount' package in "Chapter 2/popcount",
+// instead of a copy in each exercise; the functions here call it.
 
 func PopCount(x uint64) int {
-	return int(pc[byte(x>>(0*8))] +
-		pc[byte(x>>(1*8))] +
-		pc[byte(x>>(2*8))] +
-		pc[byte(x>>(3*8))] +
-		pc[byte(x>>(4*8))] +
-		pc[byte(x>>(5*8))] +
-		pc[byte(x>>(6*8))] +
-		pc[byte(x>>(7*8))])
+	return popcount.PopCount(x)
 }
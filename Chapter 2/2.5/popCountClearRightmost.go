@@ -1,11 +1,7 @@
 package popcount
 
-func PopCountClearRightmost(x uint64) int {
-	count := 0
+import "GoBookSolutions/popcount"
 
-	for x != 0 {
-		x &= x - 1
-		count++
-	}
-	return count
+func PopCountClearRightmost(x uint64) int {
+	return popcount.PopCountClearRightmost(x)
 }
@@ -0,0 +1,32 @@
+package popcount
+
+import "math/bits"
+
+/* 'bits.OnesCount64' is what the standard library offers. The compiler treats it as
+an intrinsic: on amd64 it becomes the POPCNT instruction when the CPU has it (with a
+check at run time and a software fallback when it does not), and on arm64, ppc64 and
+s390x the equivalent instruction, so it is a single instruction where that exists. */
+
+func PopCountBits(x uint64) int {
+	return bits.OnesCount64(x)
+}
+
+/* SWAR ("SIMD within a register") counts the bits of all 64 at once, treating the
+word as a vector of ever wider fields. First every 2-bit field gets the count of
+its two bits, then every 4-bit field the sum of two 2-bit counts, then every byte
+the sum of two 4-bit counts. Multiplying by 0x0101...01 finally adds all eight
+bytes into the top one. There are no loops, no branches and no table to load. */
+
+const (
+	m1  = 0x5555555555555555 // 0101...
+	m2  = 0x3333333333333333 // 00110011...
+	m4  = 0x0f0f0f0f0f0f0f0f // 0000111100001111...
+	h01 = 0x0101010101010101 // the sum of 256 to the power 0, 1, 2, 3...
+)
+
+func PopCountSWAR(x uint64) int {
+	x -= (x >> 1) & m1             // the count of each 2 bits in those 2 bits
+	x = (x & m2) + ((x >> 2) & m2) // the count of each 4 bits in those 4 bits
+	x = (x + (x >> 4)) & m4        // the count of each 8 bits in those 8 bits
+	return int((x * h01) >> 56)    // the sum of the 8 bytes, in the top byte
+}
@@ -0,0 +1,293 @@
+// Package bitset implements sets of non-negative integers as bit vectors, like the
+// 'IntSet' of chapter 6 of the book: bit 'x%64' of word 'x/64' is set if 'x' is in
+// the set. Operations on two sets work a word, that is 64 elements, at a time, and
+// counting, ranking and selecting elements come down to population counts, for which
+// we use the 'popcount' package.
+//
+// The zero value is an empty set ready to use. A set uses memory in proportion to its
+// largest element, not to its number of elements, so it suits dense sets of small
+// integers, such as IDs handed out in order.
+package bitset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"GoBookSolutions/popcount"
+)
+
+// Bitset is a set of non-negative integers.
+type Bitset struct {
+	words []uint64
+}
+
+// New returns a set of the given elements.
+func New(elems ...int) *Bitset {
+	s := new(Bitset)
+	for _, x := range elems {
+		s.Add(x)
+	}
+	return s
+}
+
+// split returns the word and the bit of element 'x'. Negative elements cannot be
+// stored, and we panic like an index out of range would.
+func split(x int) (word int, bit uint) {
+	if x < 0 {
+		panic(fmt.Sprintf("bitset: negative element %d", x))
+	}
+	return x / 64, uint(x % 64)
+}
+
+// Has reports whether the set contains 'x'.
+func (s *Bitset) Has(x int) bool {
+	if x < 0 {
+		return false
+	}
+	word, bit := split(x)
+	return word < len(s.words) && s.words[word]&(1<<bit) != 0
+}
+
+// Add adds 'x' to the set.
+func (s *Bitset) Add(x int) {
+	word, bit := split(x)
+	if word >= len(s.words) {
+		s.words = append(s.words, make([]uint64, word+1-len(s.words))...)
+	}
+	s.words[word] |= 1 << bit
+}
+
+// Remove removes 'x' from the set, if it is there.
+func (s *Bitset) Remove(x int) {
+	if x < 0 {
+		return
+	}
+	word, bit := split(x)
+	if word < len(s.words) {
+		s.words[word] &^= 1 << bit
+	}
+}
+
+// Clear removes all elements from the set.
+func (s *Bitset) Clear() {
+	s.words = nil
+}
+
+// Copy returns a copy of the set.
+func (s *Bitset) Copy() *Bitset {
+	return &Bitset{words: append([]uint64(nil), s.words...)}
+}
+
+// Count returns the number of elements of the set.
+func (s *Bitset) Count() int {
+	return popcount.CountSlice(s.words)
+}
+
+// Equal reports whether 's' and 't' have the same elements.
+func (s *Bitset) Equal(t *Bitset) bool {
+	a, b := s.trimmed(), t.trimmed()
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// trimmed returns the words of the set without the zero words at the end, which
+// removing the largest elements leaves behind.
+func (s *Bitset) trimmed() []uint64 {
+	w := s.words
+	for len(w) > 0 && w[len(w)-1] == 0 {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+/* Each operation on two sets comes in two forms: 'UnionWith' and the others change
+'s' in place, as the 'IntSet' of the book does, and 'Union' and the others leave both
+sets alone and return a new one. */
+
+// UnionWith sets 's' to the union of 's' and 't': the elements of either.
+func (s *Bitset) UnionWith(t *Bitset) {
+	for i, w := range t.words {
+		if i < len(s.words) {
+			s.words[i] |= w
+		} else {
+			s.words = append(s.words, w)
+		}
+	}
+}
+
+// IntersectWith sets 's' to the intersection of 's' and 't': the elements of both.
+func (s *Bitset) IntersectWith(t *Bitset) {
+	for i := range s.words {
+		if i < len(t.words) {
+			s.words[i] &= t.words[i]
+		} else {
+			s.words[i] = 0
+		}
+	}
+}
+
+// DifferenceWith sets 's' to the difference of 's' and 't': the elements of 's' that
+// are not in 't'.
+func (s *Bitset) DifferenceWith(t *Bitset) {
+	for i := range s.words {
+		if i < len(t.words) {
+			s.words[i] &^= t.words[i]
+		}
+	}
+}
+
+// SymmetricDifferenceWith sets 's' to the symmetric difference of 's' and 't': the
+// elements of one of them but not of the other.
+func (s *Bitset) SymmetricDifferenceWith(t *Bitset) {
+	for i, w := range t.words {
+		if i < len(s.words) {
+			s.words[i] ^= w
+		} else {
+			s.words = append(s.words, w)
+		}
+	}
+}
+
+// Union returns the union of 's' and 't'.
+func (s *Bitset) Union(t *Bitset) *Bitset {
+	u := s.Copy()
+	u.UnionWith(t)
+	return u
+}
+
+// Intersect returns the intersection of 's' and 't'.
+func (s *Bitset) Intersect(t *Bitset) *Bitset {
+	u := s.Copy()
+	u.IntersectWith(t)
+	return u
+}
+
+// Difference returns the elements of 's' that are not in 't'.
+func (s *Bitset) Difference(t *Bitset) *Bitset {
+	u := s.Copy()
+	u.DifferenceWith(t)
+	return u
+}
+
+// SymmetricDifference returns the elements of 's' or 't' but not of both.
+func (s *Bitset) SymmetricDifference(t *Bitset) *Bitset {
+	u := s.Copy()
+	u.SymmetricDifferenceWith(t)
+	return u
+}
+
+// Each calls 'f' for each element of the set, in increasing order, until 'f' returns
+// false. We take the lowest set bit of a word with 'bits.TrailingZeros64' and clear it
+// with 'w &= w-1', as 'PopCountClearRightmost' does, so each word costs one step per
+// element rather than 64.
+func (s *Bitset) Each(f func(x int) bool) {
+	for i, w := range s.words {
+		for w != 0 {
+			if !f(64*i + bits.TrailingZeros64(w)) {
+				return
+			}
+			w &= w - 1
+		}
+	}
+}
+
+// Elems returns the elements of the set in increasing order.
+func (s *Bitset) Elems() []int {
+	elems := make([]int, 0, s.Count())
+	s.Each(func(x int) bool {
+		elems = append(elems, x)
+		return true
+	})
+	return elems
+}
+
+// Rank returns the number of elements of the set that are smaller than 'x'.
+func (s *Bitset) Rank(x int) int {
+	if x <= 0 {
+		return 0
+	}
+	word, bit := split(x)
+	if word >= len(s.words) {
+		return s.Count()
+	}
+	return popcount.CountSlice(s.words[:word]) + popcount.Count(s.words[word]&(1<<bit-1))
+}
+
+// Select returns the element of rank 'k', that is the 'k'-th smallest counting from
+// zero, so that 's.Rank(s.Select(k)) == k'. It reports false if the set has 'k' or
+// fewer elements.
+func (s *Bitset) Select(k int) (int, bool) {
+	if k < 0 {
+		return 0, false
+	}
+	for i, w := range s.words {
+		n := popcount.Count(w)
+		if k >= n {
+			k -= n
+			continue
+		}
+		// The element is in this word: drop its 'k' lowest set bits, and the next
+		// one is it.
+		for ; k > 0; k-- {
+			w &= w - 1
+		}
+		return 64*i + bits.TrailingZeros64(w), true
+	}
+	return 0, false
+}
+
+// String returns the set as "{1 2 3}".
+func (s *Bitset) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	s.Each(func(x int) bool {
+		if buf.Len() > len("{") {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%d", x)
+		return true
+	})
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+/* The binary form of a set is its words, each in 8 bytes, little-endian, without
+the zero words at the end. It is as long as the largest element needs and no longer,
+the empty set is empty, and the same set always gives the same bytes. */
+
+// ErrInvalidLength is returned for binary data that is not a whole number of words.
+var ErrInvalidLength = errors.New("bitset: binary data is not a multiple of 8 bytes")
+
+// MarshalBinary implements 'encoding.BinaryMarshaler'.
+func (s *Bitset) MarshalBinary() ([]byte, error) {
+	w := s.trimmed()
+	data := make([]byte, 8*len(w))
+	for i, x := range w {
+		binary.LittleEndian.PutUint64(data[8*i:], x)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements 'encoding.BinaryUnmarshaler'. It replaces the elements of
+// the set with those in 'data'.
+func (s *Bitset) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return ErrInvalidLength
+	}
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	s.words = words
+	return nil
+}
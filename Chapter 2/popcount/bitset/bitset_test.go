@@ -0,0 +1,311 @@
+// These tests check the set operations against a map holding the same elements, and
+// rank, select, the binary form and 'String' on small sets. The benchmarks use a dense
+// set, with every other integer below 128K, and a sparse one, with one in 1000
+// below 64M:
+//
+//	go test
+//	go test -bench=.
+
+package bitset
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// random returns a set of 'n' random elements below 'max' and the same elements as a map.
+func random(r *rand.Rand, n, max int) (*Bitset, map[int]bool) {
+	s, m := New(), make(map[int]bool)
+	for i := 0; i < n; i++ {
+		x := r.Intn(max)
+		s.Add(x)
+		m[x] = true
+	}
+	return s, m
+}
+
+// sorted returns the keys of 'm' in increasing order.
+func sorted(m map[int]bool) []int {
+	elems := make([]int, 0, len(m))
+	for x := range m {
+		elems = append(elems, x)
+	}
+	sort.Ints(elems)
+	return elems
+}
+
+func TestAgainstMap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		max := 1 + r.Intn(1000)
+		s, ms := random(r, r.Intn(200), max)
+		u, mu := random(r, r.Intn(200), max)
+		for i := 0; i < 50; i++ {
+			x := r.Intn(max)
+			s.Remove(x)
+			delete(ms, x)
+		}
+		if got, want := s.Elems(), sorted(ms); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Elems() = %v, expected %v", got, want)
+		}
+		if s.Count() != len(ms) {
+			t.Fatalf("Count() = %d, expected %d", s.Count(), len(ms))
+		}
+		for x := -1; x <= max+64; x++ {
+			if s.Has(x) != ms[x] {
+				t.Fatalf("Has(%d) = %t", x, s.Has(x))
+			}
+		}
+
+		union, inter, diff, sym := map[int]bool{}, map[int]bool{}, map[int]bool{}, map[int]bool{}
+		for x := range ms {
+			union[x] = true
+			if mu[x] {
+				inter[x] = true
+			} else {
+				diff[x] = true
+				sym[x] = true
+			}
+		}
+		for x := range mu {
+			union[x] = true
+			if !ms[x] {
+				sym[x] = true
+			}
+		}
+		for _, test := range []struct {
+			name string
+			got  *Bitset
+			want map[int]bool
+		}{
+			{"Union", s.Union(u), union},
+			{"Intersect", s.Intersect(u), inter},
+			{"Difference", s.Difference(u), diff},
+			{"SymmetricDifference", s.SymmetricDifference(u), sym},
+		} {
+			if !test.got.Equal(New(sorted(test.want)...)) {
+				t.Fatalf("%s(%v, %v) = %v", test.name, s, u, test.got)
+			}
+		}
+		if !s.Equal(New(sorted(ms)...)) {
+			t.Fatalf("the operations changed their operand %v", s)
+		}
+	}
+}
+
+func TestRankSelect(t *testing.T) {
+	s := New(0, 3, 63, 64, 65, 200, 1000)
+	elems := s.Elems()
+	for k, x := range elems {
+		if got, ok := s.Select(k); !ok || got != x {
+			t.Errorf("Select(%d) = %d, %t; expected %d", k, got, ok, x)
+		}
+		if got := s.Rank(x); got != k {
+			t.Errorf("Rank(%d) = %d, expected %d", x, got, k)
+		}
+		if got := s.Rank(x + 1); got != k+1 {
+			t.Errorf("Rank(%d) = %d, expected %d", x+1, got, k+1)
+		}
+	}
+	if _, ok := s.Select(len(elems)); ok {
+		t.Errorf("Select(%d) found an element in a set of %d", len(elems), len(elems))
+	}
+	if got := s.Rank(1 << 20); got != len(elems) {
+		t.Errorf("Rank(1<<20) = %d, expected %d", got, len(elems))
+	}
+	if got := s.Rank(-5); got != 0 {
+		t.Errorf("Rank(-5) = %d, expected 0", got)
+	}
+}
+
+func TestBinary(t *testing.T) {
+	s := New(1, 64, 129, 500)
+	s.Add(10000)
+	s.Remove(10000) // leaves zero words that are not written
+	data, err := s.MarshalBinary()
+	if err != nil || len(data) != 8*8 {
+		t.Fatalf("MarshalBinary() = %d bytes, %v; expected 64 bytes", len(data), err)
+	}
+	var back Bitset
+	if err := back.UnmarshalBinary(data); err != nil || !back.Equal(s) {
+		t.Errorf("UnmarshalBinary(MarshalBinary(%v)) = %v, %v", s, &back, err)
+	}
+	if data, _ := New().MarshalBinary(); len(data) != 0 {
+		t.Errorf("MarshalBinary of the empty set = %x, expected nothing", data)
+	}
+	if err := back.UnmarshalBinary(data[:7]); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("UnmarshalBinary(7 bytes) = %v, expected ErrInvalidLength", err)
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, test := range []struct {
+		s    *Bitset
+		want string
+	}{
+		{New(), "{}"},
+		{New(42), "{42}"},
+		{New(144, 9, 1), "{1 9 144}"},
+	} {
+		if got := test.s.String(); got != test.want {
+			t.Errorf("String() = %q, expected %q", got, test.want)
+		}
+	}
+}
+
+func TestNegative(t *testing.T) {
+	s := New(1)
+	s.Remove(-1)
+	if s.Has(-1) {
+		t.Errorf("Has(-1) = true")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Add(-1) did not panic")
+		}
+	}()
+	s.Add(-1)
+}
+
+// dense has every other integer below 128K; sparse has one in 1000 below 64M, so both
+// have about 64K elements but sparse takes 500 times the memory.
+func dense() *Bitset {
+	s := New()
+	for x := 0; x < 1<<17; x += 2 {
+		s.Add(x)
+	}
+	return s
+}
+
+func sparse() *Bitset {
+	s := New()
+	for x := 0; x < 1<<26; x += 2000 {
+		s.Add(x)
+		s.Add(x + 1000)
+	}
+	return s
+}
+
+var sets = []struct {
+	name string
+	make func() *Bitset
+}{{"dense", dense}, {"sparse", sparse}}
+
+var sink int
+
+func BenchmarkAdd(b *testing.B) {
+	for _, set := range sets {
+		elems := set.make().Elems()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s := New()
+				for _, x := range elems {
+					s.Add(x)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHas(b *testing.B) {
+	for _, set := range sets {
+		s := set.make()
+		elems := s.Elems()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if s.Has(elems[i%len(elems)] + 1) {
+					sink++
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCount(b *testing.B) {
+	for _, set := range sets {
+		s := set.make()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink += s.Count()
+			}
+		})
+	}
+}
+
+func BenchmarkUnion(b *testing.B) {
+	for _, set := range sets {
+		s := set.make()
+		t := s.Copy()
+		t.SymmetricDifferenceWith(New(1, 3, 5))
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink += len(s.Union(t).words)
+			}
+		})
+	}
+}
+
+func BenchmarkIntersectWith(b *testing.B) {
+	for _, set := range sets {
+		s, t := set.make(), set.make()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.IntersectWith(t)
+			}
+		})
+	}
+}
+
+func BenchmarkEach(b *testing.B) {
+	for _, set := range sets {
+		s := set.make()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Each(func(x int) bool {
+					sink += x
+					return true
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkRank(b *testing.B) {
+	for _, set := range sets {
+		s := set.make()
+		elems := s.Elems()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink += s.Rank(elems[i%len(elems)])
+			}
+		})
+	}
+}
+
+func BenchmarkSelect(b *testing.B) {
+	for _, set := range sets {
+		s := set.make()
+		n := s.Count()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				x, _ := s.Select(i % n)
+				sink += x
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalBinary(b *testing.B) {
+	for _, set := range sets {
+		s := set.make()
+		b.Run(set.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data, _ := s.MarshalBinary()
+				sink += len(data)
+			}
+		})
+	}
+}
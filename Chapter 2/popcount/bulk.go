@@ -0,0 +1,137 @@
+package popcount
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+/* Counting the bits of a whole slice is where the differences between the variants
+add up. With a popcount instruction the best we can do in Go is to call it on every
+word, unrolled four times so the loop overhead is shared and the CPU can run the
+independent counts side by side.
+
+Without one, counting every word in software is slow, and the Harley-Seal algorithm
+counts far fewer of them. It treats the bits of the words as columns and adds them up
+with carry-save adders, the circuit that adds three bits into a sum bit and a carry
+bit, but working on 64 columns at once. Sixteen words go in, and only one word, whose
+every bit stands for sixteen ones, comes out to be counted; the partial sums (the
+'ones', 'twos', 'fours' and 'eights') are carried from one block to the next and
+counted once at the end. */
+
+// PopCountSliceUnrolled counts the bits of every word with 'bits.OnesCount64', four
+// words at a time.
+func PopCountSliceUnrolled(words []uint64) int {
+	var c0, c1, c2, c3 int
+	i := 0
+	for ; i+4 <= len(words); i += 4 {
+		c0 += bits.OnesCount64(words[i])
+		c1 += bits.OnesCount64(words[i+1])
+		c2 += bits.OnesCount64(words[i+2])
+		c3 += bits.OnesCount64(words[i+3])
+	}
+	for ; i < len(words); i++ {
+		c0 += bits.OnesCount64(words[i])
+	}
+	return c0 + c1 + c2 + c3
+}
+
+// csa is a carry-save adder: for each of the 64 bit positions it adds the bits of
+// 'a', 'b' and 'c' and returns the carry in 'high' and the sum in 'low'.
+func csa(a, b, c uint64) (high, low uint64) {
+	u := a ^ b
+	return a&b | u&c, u ^ c
+}
+
+// harleySeal holds the partial sums of the Harley-Seal algorithm: 'total' counts
+// sixteens, and each bit of 'ones', 'twos', 'fours' and 'eights' stands for that
+// many ones.
+type harleySeal struct {
+	total, ones, twos, fours, eights uint64
+}
+
+// add adds a block of sixteen words.
+func (h *harleySeal) add(w *[16]uint64) {
+	var twosA, twosB, foursA, foursB, eightsA, eightsB, sixteens uint64
+	twosA, h.ones = csa(h.ones, w[0], w[1])
+	twosB, h.ones = csa(h.ones, w[2], w[3])
+	foursA, h.twos = csa(h.twos, twosA, twosB)
+	twosA, h.ones = csa(h.ones, w[4], w[5])
+	twosB, h.ones = csa(h.ones, w[6], w[7])
+	foursB, h.twos = csa(h.twos, twosA, twosB)
+	eightsA, h.fours = csa(h.fours, foursA, foursB)
+	twosA, h.ones = csa(h.ones, w[8], w[9])
+	twosB, h.ones = csa(h.ones, w[10], w[11])
+	foursA, h.twos = csa(h.twos, twosA, twosB)
+	twosA, h.ones = csa(h.ones, w[12], w[13])
+	twosB, h.ones = csa(h.ones, w[14], w[15])
+	foursB, h.twos = csa(h.twos, twosA, twosB)
+	eightsB, h.fours = csa(h.fours, foursA, foursB)
+	sixteens, h.eights = csa(h.eights, eightsA, eightsB)
+	h.total += uint64(PopCountSWAR(sixteens))
+}
+
+// count returns the number of ones added so far.
+func (h *harleySeal) count() int {
+	return int(16*h.total + 8*uint64(PopCountSWAR(h.eights)) + 4*uint64(PopCountSWAR(h.fours)) +
+		2*uint64(PopCountSWAR(h.twos)) + uint64(PopCountSWAR(h.ones)))
+}
+
+// PopCountSliceHarleySeal counts the bits of 'words' with the Harley-Seal algorithm,
+// counting the words that are left with 'PopCountSWAR'.
+func PopCountSliceHarleySeal(words []uint64) int {
+	var h harleySeal
+	i := 0
+	for ; i+16 <= len(words); i += 16 {
+		h.add((*[16]uint64)(words[i : i+16]))
+	}
+	total := h.count()
+	for ; i < len(words); i++ {
+		total += PopCountSWAR(words[i])
+	}
+	return total
+}
+
+/* Bytes are read as little-endian words (which order does not matter for counting,
+as long as every byte is read once). The last few bytes that do not make a word
+are counted with the table of 'PopCount'. */
+
+// PopCountBytesUnrolled counts the bits of 'b' like 'PopCountSliceUnrolled'.
+func PopCountBytesUnrolled(b []byte) int {
+	var c0, c1, c2, c3 int
+	for ; len(b) >= 32; b = b[32:] {
+		c0 += bits.OnesCount64(binary.LittleEndian.Uint64(b))
+		c1 += bits.OnesCount64(binary.LittleEndian.Uint64(b[8:]))
+		c2 += bits.OnesCount64(binary.LittleEndian.Uint64(b[16:]))
+		c3 += bits.OnesCount64(binary.LittleEndian.Uint64(b[24:]))
+	}
+	for ; len(b) >= 8; b = b[8:] {
+		c0 += bits.OnesCount64(binary.LittleEndian.Uint64(b))
+	}
+	return c0 + c1 + c2 + c3 + popCountTail(b)
+}
+
+// PopCountBytesHarleySeal counts the bits of 'b' like 'PopCountSliceHarleySeal'.
+func PopCountBytesHarleySeal(b []byte) int {
+	var h harleySeal
+	var w [16]uint64
+	for ; len(b) >= 128; b = b[128:] {
+		for i := range w {
+			w[i] = binary.LittleEndian.Uint64(b[8*i:])
+		}
+		h.add(&w)
+	}
+	total := h.count()
+	for ; len(b) >= 8; b = b[8:] {
+		total += PopCountSWAR(binary.LittleEndian.Uint64(b))
+	}
+	return total + popCountTail(b)
+}
+
+// popCountTail counts the bits of fewer than eight bytes.
+func popCountTail(b []byte) int {
+	n := 0
+	for _, c := range b {
+		n += int(pc[c])
+	}
+	return n
+}
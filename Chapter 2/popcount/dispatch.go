@@ -0,0 +1,49 @@
+package popcount
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/cpu"
+)
+
+/* Which variant is fastest depends on the machine. Where the CPU has a popcount
+instruction that the compiler uses for 'bits.OnesCount64', nothing in software comes
+close, one word or many. Where it does not, 'bits.OnesCount64' is itself a software
+count much like 'PopCountSWAR', and for slices Harley-Seal wins. We look at the CPU
+once, when the package is initialized, and point 'Count', 'CountSlice' and
+'CountBytes' at the variants to use, so that calling them costs only an indirect
+call. */
+
+var (
+	// Count returns the number of set bits of a word.
+	Count func(x uint64) int
+	// CountSlice returns the number of set bits of all words of a slice.
+	CountSlice func(words []uint64) int
+	// CountBytes returns the number of set bits of all bytes of a slice.
+	CountBytes func(b []byte) int
+	// Implementation names the variants chosen: "hardware" or "software".
+	Implementation string
+)
+
+func init() {
+	if hasPopcountInstruction() {
+		Count, CountSlice, CountBytes = PopCountBits, PopCountSliceUnrolled, PopCountBytesUnrolled
+		Implementation = "hardware"
+	} else {
+		Count, CountSlice, CountBytes = PopCountSWAR, PopCountSliceHarleySeal, PopCountBytesHarleySeal
+		Implementation = "software"
+	}
+}
+
+// hasPopcountInstruction reports whether 'bits.OnesCount64' compiles to an
+// instruction on this machine. On amd64 that depends on the CPU; arm64, ppc64 and
+// s390x always have one.
+func hasPopcountInstruction() bool {
+	switch runtime.GOARCH {
+	case "amd64":
+		return cpu.X86.HasPOPCNT
+	case "arm64", "ppc64", "ppc64le", "s390x":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,5 @@
+module GoBookSolutions/popcount
+
+go 1.20
+
+require golang.org/x/sys v0.15.0
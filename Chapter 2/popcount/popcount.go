@@ -0,0 +1,113 @@
+// Package popcount counts the set bits of integers, in every way the exercises of
+// chapter 2 try out and a few more. Exercises 2.3 to 2.5 and 4.1 each used to carry
+// their own copy of the table and of 'PopCount'; they now all call this package.
+//
+// Every variant for a single word has the signature 'func(uint64) int' and is listed
+// in 'Variants', those for slices in 'SliceVariants' and 'BytesVariants', so that the
+// tests and benchmarks can go through all of them. 'Count', 'CountSlice' and
+// 'CountBytes' are the fastest variants for the machine the program runs on.
+//
+// The 'bitset' package below this one builds sets of integers on these counts.
+package popcount
+
+// pc[i] is the population count of i. It is shared by every variant that looks bits
+// up a byte at a time.
+var pc [256]byte
+
+func init() {
+	for i := range pc {
+		pc[i] = pc[i/2] + byte(i&1)
+	}
+}
+
+// PopCount returns the population count of 'x' as the book writes it: one
+// expression adding up the table entries of its eight bytes.
+func PopCount(x uint64) int {
+	return int(pc[byte(x>>(0*8))] +
+		pc[byte(x>>(1*8))] +
+		pc[byte(x>>(2*8))] +
+		pc[byte(x>>(3*8))] +
+		pc[byte(x>>(4*8))] +
+		pc[byte(x>>(5*8))] +
+		pc[byte(x>>(6*8))] +
+		pc[byte(x>>(7*8))])
+}
+
+// PopCountLoop uses the same table as 'PopCount', in a loop over the eight bytes
+// (exercise 2.3).
+func PopCountLoop(x uint64) int {
+	count := 0
+	for i := 0; i < 8; i++ {
+		count += int(pc[byte(x>>(i*8))])
+	}
+	return count
+}
+
+// PopCountShift tests the rightmost bit of 'x' 64 times, shifting it right each
+// time (exercise 2.4).
+func PopCountShift(x uint64) int {
+	count := 0
+
+	for i := 0; i < 64; i++ {
+		if x&1 == 1 {
+			count++
+		}
+		x >>= 1
+	}
+	return count
+}
+
+// PopCountClearRightmost clears the rightmost set bit of 'x' with 'x&(x-1)' until
+// none is left, so it loops once per set bit (exercise 2.5).
+func PopCountClearRightmost(x uint64) int {
+	count := 0
+
+	for x != 0 {
+		x &= x - 1
+		count++
+	}
+	return count
+}
+
+// Variant is a named function counting the bits of one word.
+type Variant struct {
+	Name  string
+	Count func(x uint64) int
+}
+
+// SliceVariant is a named function counting the bits of a slice of words.
+type SliceVariant struct {
+	Name  string
+	Count func(words []uint64) int
+}
+
+// BytesVariant is a named function counting the bits of a slice of bytes.
+type BytesVariant struct {
+	Name  string
+	Count func(b []byte) int
+}
+
+// Variants lists every variant for one word, the chosen 'Count' last.
+var Variants = []Variant{
+	{"Expression", PopCount},
+	{"Loop", PopCountLoop},
+	{"Shift", PopCountShift},
+	{"ClearRightmost", PopCountClearRightmost},
+	{"Bits", PopCountBits},
+	{"SWAR", PopCountSWAR},
+	{"Count", func(x uint64) int { return Count(x) }},
+}
+
+// SliceVariants lists every variant for slices of words, the chosen 'CountSlice' last.
+var SliceVariants = []SliceVariant{
+	{"Unrolled", PopCountSliceUnrolled},
+	{"HarleySeal", PopCountSliceHarleySeal},
+	{"CountSlice", func(words []uint64) int { return CountSlice(words) }},
+}
+
+// BytesVariants lists every variant for slices of bytes, the chosen 'CountBytes' last.
+var BytesVariants = []BytesVariant{
+	{"Unrolled", PopCountBytesUnrolled},
+	{"HarleySeal", PopCountBytesHarleySeal},
+	{"CountBytes", func(b []byte) int { return CountBytes(b) }},
+}
@@ -0,0 +1,155 @@
+// These tests check every variant against a plain count of the bits, on all 16-bit
+// values, on edge cases and on random words, and the slice variants on lengths around
+// their block sizes. The benchmarks run every variant on the same inputs:
+//
+//	go test
+//	go test -bench=.
+//	go test -bench=Slice/1024
+
+package popcount
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// naive counts the bits of 'x' one at a time, as the reference for all the variants.
+func naive(x uint64) int {
+	n := 0
+	for i := 0; i < 64; i++ {
+		n += int(x >> i & 1)
+	}
+	return n
+}
+
+// words returns 'n' random words, the same ones every time.
+func words(n int) []uint64 {
+	r := rand.New(rand.NewSource(int64(n)))
+	w := make([]uint64, n)
+	for i := range w {
+		w[i] = r.Uint64()
+	}
+	return w
+}
+
+// edgeCases are words whose bits sit where mistakes are made: none, all, single bits,
+// single bytes, alternating patterns and the masks of 'PopCountSWAR'.
+func edgeCases() []uint64 {
+	xs := []uint64{0, ^uint64(0), 0xDEADBEEF, m1, m2, m4, h01, ^uint64(m1), ^uint64(m2), ^uint64(m4)}
+	for i := 0; i < 64; i++ {
+		xs = append(xs, 1<<i, ^uint64(1<<i), 1<<i-1)
+	}
+	for i := 0; i < 8; i++ {
+		xs = append(xs, 0xff<<(8*i))
+	}
+	return xs
+}
+
+func TestVariants(t *testing.T) {
+	inputs := append(edgeCases(), words(10000)...)
+	for x := uint64(0); x < 1<<16; x++ {
+		inputs = append(inputs, x, x<<48, x*0x0001000100010001)
+	}
+	for _, v := range Variants {
+		for _, x := range inputs {
+			if got, want := v.Count(x), naive(x); got != want {
+				t.Errorf("%s(%#x) = %d, expected %d", v.Name, x, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestSliceVariants(t *testing.T) {
+	// The lengths go around the blocks of four and sixteen words, and a few bytes that
+	// do not make a word are added to the byte slices.
+	for _, n := range []int{0, 1, 3, 4, 5, 15, 16, 17, 31, 32, 33, 100, 1000} {
+		w := words(n)
+		want := 0
+		for _, x := range w {
+			want += naive(x)
+		}
+		for _, v := range SliceVariants {
+			if got := v.Count(w); got != want {
+				t.Errorf("%s(%d words) = %d, expected %d", v.Name, n, got, want)
+			}
+		}
+		b := make([]byte, 8*n+n%8)
+		rand.New(rand.NewSource(int64(n))).Read(b)
+		want = 0
+		for _, c := range b {
+			want += naive(uint64(c))
+		}
+		for _, v := range BytesVariants {
+			if got := v.Count(b); got != want {
+				t.Errorf("%s(%d bytes) = %d, expected %d", v.Name, len(b), got, want)
+			}
+		}
+	}
+}
+
+var sink int
+
+// BenchmarkWord runs every variant on a word with few bits set, one with half of them
+// and one with all of them, since the loops of 'PopCountClearRightmost' depend on it.
+func BenchmarkWord(b *testing.B) {
+	inputs := []struct {
+		name string
+		x    uint64
+	}{{"sparse", 0x8000000000000001}, {"deadbeef", 0xDEADBEEF}, {"dense", ^uint64(0)}}
+	for _, v := range Variants {
+		for _, in := range inputs {
+			b.Run(v.Name+"/"+in.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					sink += v.Count(in.x)
+				}
+			})
+		}
+	}
+}
+
+// bulkSizes go from 8 words to 1M words (8 MiB, more than most caches hold).
+var bulkSizes = []int{8, 64, 1 << 10, 1 << 16, 1 << 20}
+
+// BenchmarkSlice runs the slice variants and, for comparison, each word variant in a
+// plain loop, on every size.
+func BenchmarkSlice(b *testing.B) {
+	variants := append([]SliceVariant(nil), SliceVariants...)
+	for _, v := range Variants {
+		count := v.Count
+		variants = append(variants, SliceVariant{"Loop" + v.Name, func(w []uint64) int {
+			n := 0
+			for _, x := range w {
+				n += count(x)
+			}
+			return n
+		}})
+	}
+	for _, v := range variants {
+		for _, n := range bulkSizes {
+			w := words(n)
+			b.Run(fmt.Sprintf("%s/%d", v.Name, n), func(b *testing.B) {
+				b.SetBytes(int64(8 * n))
+				for i := 0; i < b.N; i++ {
+					sink += v.Count(w)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkBytes(b *testing.B) {
+	for _, v := range BytesVariants {
+		for _, n := range bulkSizes {
+			buf := make([]byte, 8*n)
+			rand.New(rand.NewSource(int64(n))).Read(buf)
+			b.Run(fmt.Sprintf("%s/%d", v.Name, len(buf)), func(b *testing.B) {
+				b.SetBytes(int64(len(buf)))
+				for i := 0; i < b.N; i++ {
+					sink += v.Count(buf)
+				}
+			})
+		}
+	}
+}
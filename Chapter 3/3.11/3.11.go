@@ -4,11 +4,27 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+
+	"GoBookSolutions/3.11/numfmt"
 )
 
-func comma(s string) string {
+func comma(s string) (string, error) {
 	var buf bytes.Buffer
 
+	/* Before anything else we validate the input with 'numfmt.Normalize', which returns an error for
+	anything that is not a number ("abc", "12.3.4", "+" or ""), and writes numbers in exponent notation
+	out in full, so that "1e10" becomes "10000000000". Without it, an input with no digits before the
+	decimal point would make 'integralPart[:firstCommaPos]' below panic. 'Normalize' also drops
+	leading zeros and a '+' sign; we keep the sign, as the code below always did. */
+	normalized, err := numfmt.Normalize(s)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(s, "+") {
+		normalized = "+" + normalized
+	}
+	s = normalized
+
 	/* The code here checks whether the input number string 's' has a sign (positive or negative) by examining
 	its prefix. If the string starts with either '+' or '-', it means there is a sign. The sign is extracted
 	from the input string and stored in the sign variable. The sign is then removed from the input string 's'
@@ -49,15 +65,26 @@ func comma(s string) string {
 	part (if applicable) to create the final modified string named 'result'. This string is then returned as the output
 	of the 'comma' function. */
 	result := sign + buf.String() + fractionalPart
-	return result
+	return result, nil
 }
 
 func main() {
 	number1 := "1234567890"
-	result := comma(number1)
-	fmt.Println(result)
-
 	number2 := "-9876543.210"
-	result2 := comma(number2)
-	fmt.Println(result2)
+	for _, number := range []string{number1, number2, "1.5e6", "12.3.4"} {
+		result, err := comma(number)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(result)
+	}
+
+	/* The 'numfmt' package does the same for other locales, whose separators and group sizes
+	differ, and for amounts of money. */
+	for _, l := range []*numfmt.Locale{numfmt.English, numfmt.Indian, numfmt.Swiss, numfmt.French} {
+		n, _ := l.Format(number2)
+		m, _ := l.FormatCurrency(number1, numfmt.EUR, numfmt.HalfEven)
+		fmt.Printf("%-6s %s\t%s\n", l.Tag, n, m)
+	}
 }
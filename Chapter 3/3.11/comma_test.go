@@ -0,0 +1,53 @@
+/* These tests check that 'comma' rejects invalid input instead of panicking or
+returning garbage, and that it handles exponent notation. 'FuzzComma' runs its seed
+inputs with 'go test'; run 'go test -fuzz FuzzComma' to let it search for inputs that
+make 'comma' panic. */
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"GoBookSolutions/3.11/numfmt"
+)
+
+func TestComma(t *testing.T) {
+	tests := []struct{ s, want string }{
+		{"1234567890", "1,234,567,890"},
+		{"-9876543.210", "-9,876,543.210"},
+		{"+1234", "+1,234"},
+		{"123", "123"},
+		{".5", "0.5"},
+		{"1e10", "10,000,000,000"},
+		{"-1.2345E3", "-1,234.5"},
+		{"000123456", "123,456"},
+	}
+	for _, test := range tests {
+		if got, err := comma(test.s); err != nil || got != test.want {
+			t.Errorf("comma(%q) = %q, %v; expected %q", test.s, got, err, test.want)
+		}
+	}
+	for _, s := range []string{"", "+", "-", "abc", "12.3.4", ".", "1e", "1,000"} {
+		if got, err := comma(s); !errors.Is(err, numfmt.ErrSyntax) {
+			t.Errorf("comma(%q) = %q, %v; expected ErrSyntax", s, got, err)
+		}
+	}
+}
+
+func FuzzComma(f *testing.F) {
+	for _, s := range []string{"1234567890", "-9876543.210", "1e10", "12.3.4", "abc", "+", "", ".5", "-.e1"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := comma(s)
+		if err != nil {
+			return
+		}
+		n, _ := numfmt.Normalize(s)
+		if strings.ReplaceAll(strings.TrimPrefix(got, "+"), ",", "") != n {
+			t.Errorf("comma(%q) = %q, which is not %q with commas", s, got, n)
+		}
+	})
+}
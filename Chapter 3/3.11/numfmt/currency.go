@@ -0,0 +1,36 @@
+package numfmt
+
+import "strings"
+
+// Currency is a currency: its ISO 4217 code, the symbol it is written with, and the
+// number of decimals of its minor unit (2 for cents, 0 for the yen). A negative
+// number rounds to tens, hundreds and so on, as 'FormatRound' does.
+type Currency struct {
+	Code     string
+	Symbol   string
+	Decimals int
+}
+
+var (
+	USD = Currency{"USD", "$", 2}
+	EUR = Currency{"EUR", "€", 2}
+	GBP = Currency{"GBP", "£", 2}
+	CHF = Currency{"CHF", "CHF", 2}
+	INR = Currency{"INR", "₹", 2}
+	JPY = Currency{"JPY", "¥", 0}
+	SEK = Currency{"SEK", "kr", 2}
+	KWD = Currency{"KWD", "KWD", 3}
+)
+
+// FormatCurrency writes an amount of money in currency 'c': rounded to the decimals
+// of the currency with 'mode', and placed next to the symbol as the locale's
+// 'Currency' pattern says. A minus sign goes in front of everything, as in -$5.00.
+func (l *Locale) FormatCurrency(x any, c Currency, mode RoundingMode) (string, error) {
+	d, err := toDecimal(x)
+	if err != nil {
+		return "", err
+	}
+	d = roundTo(d, c.Decimals, mode)
+	s := strings.Replace(l.Currency, "#", l.digits(d), 1)
+	return l.sign(d) + strings.Replace(s, "¤", c.Symbol, 1), nil
+}
@@ -0,0 +1,219 @@
+package numfmt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/* Every input is turned into a decimal string first, and everything after that,
+rounding included, works on the digits. This way a float64 such as 2.675 is rounded
+as the 2.675 it prints as, to 2.68, and not as the binary value just below it, and a
+'big.Int' with fifty digits keeps all of them. A float64 is read as the shortest
+decimal that gives back the same float64, a 'big.Float' likewise at its own
+precision. */
+
+// decimal is a number as its digits: 'int' holds the integral part without leading
+// zeros ("0" for none) and 'frac' the fractional digits, possibly empty.
+type decimal struct {
+	neg       bool
+	int, frac string
+}
+
+// toDecimal converts one of the supported inputs to a decimal.
+func toDecimal(x any) (decimal, error) {
+	switch x := x.(type) {
+	case string:
+		return parseDecimal(x)
+	case int:
+		return parseDecimal(strconv.Itoa(x))
+	case int64:
+		return parseDecimal(strconv.FormatInt(x, 10))
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return decimal{}, fmt.Errorf("numfmt: %v is not a finite number", x)
+		}
+		return parseDecimal(strconv.FormatFloat(x, 'f', -1, 64))
+	case *big.Int:
+		return parseDecimal(x.String())
+	case *big.Float:
+		if x.IsInf() {
+			return decimal{}, fmt.Errorf("numfmt: %v is not a finite number", x)
+		}
+		return parseDecimal(x.Text('f', -1))
+	}
+	return decimal{}, fmt.Errorf("numfmt: cannot format %T", x)
+}
+
+// maxExponent bounds the exponent of numbers in scientific notation, since "1e1000000"
+// would otherwise be written out as a million zeros.
+const maxExponent = 10000
+
+var (
+	ErrSyntax = errors.New("invalid number")
+	ErrRange  = errors.New("exponent out of range")
+)
+
+// NumError records a failed conversion, like 'strconv.NumError'.
+type NumError struct {
+	Num string
+	Err error
+}
+
+func (e *NumError) Error() string {
+	return "numfmt: parsing " + strconv.Quote(e.Num) + ": " + e.Err.Error()
+}
+
+func (e *NumError) Unwrap() error { return e.Err }
+
+// Normalize validates a number written as a string and returns it in plain decimal
+// notation: without a '+' sign, leading zeros or an exponent, so that "+001.50" is
+// "1.50" and "-1.5e3" is "-1500". Trailing zeros of the fraction are significant
+// and kept. The error is a '*NumError' wrapping 'ErrSyntax' or 'ErrRange'.
+func Normalize(s string) (string, error) {
+	d, err := parseDecimal(s)
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+func (d decimal) String() string {
+	s := d.int
+	if d.neg {
+		s = "-" + s
+	}
+	if d.frac != "" {
+		s += "." + d.frac
+	}
+	return s
+}
+
+// parseDecimal reads a number: an optional sign, digits with an optional point among
+// or around them, and an optional exponent, 'e' or 'E' followed by an integer. There
+// must be at least one digit before the exponent.
+func parseDecimal(s string) (decimal, error) {
+	var d decimal
+	t := s
+	if t != "" && (t[0] == '+' || t[0] == '-') {
+		d.neg = t[0] == '-'
+		t = t[1:]
+	}
+	mant, exp, hasExp := strings.Cut(strings.ToLower(t), "e")
+	d.int, d.frac, _ = strings.Cut(mant, ".")
+	if d.int == "" && d.frac == "" || !allDigits(d.int) || !allDigits(d.frac) {
+		return decimal{}, &NumError{s, ErrSyntax}
+	}
+	if hasExp {
+		e, err := strconv.Atoi(exp)
+		switch {
+		case errors.Is(err, strconv.ErrRange) || err == nil && (e > maxExponent || e < -maxExponent):
+			return decimal{}, &NumError{s, ErrRange}
+		case err != nil:
+			return decimal{}, &NumError{s, ErrSyntax}
+		}
+		d = d.shift(e)
+	}
+	return d.normalize(), nil
+}
+
+// shift moves the decimal point of 'd' by 'e' places, to the right if 'e' is positive.
+func (d decimal) shift(e int) decimal {
+	digits := d.int + d.frac
+	point := len(d.int) + e
+	switch {
+	case point <= 0:
+		d.int, d.frac = "0", strings.Repeat("0", -point)+digits
+	case point >= len(digits):
+		d.int, d.frac = digits+strings.Repeat("0", point-len(digits)), ""
+	default:
+		d.int, d.frac = digits[:point], digits[point:]
+	}
+	return d
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize strips leading zeros and drops the sign of zero.
+func (d decimal) normalize() decimal {
+	d.int = strings.TrimLeft(d.int, "0")
+	if d.int == "" {
+		d.int = "0"
+	}
+	if d.int == "0" && strings.Trim(d.frac, "0") == "" {
+		d.neg = false
+	}
+	return d
+}
+
+// RoundingMode says which way to round a number whose digits do not all fit.
+type RoundingMode int
+
+const (
+	HalfEven RoundingMode = iota // to the nearest, ties to an even digit ("banker's rounding")
+	HalfUp                       // to the nearest, ties away from zero, as taught at school
+	HalfDown                     // to the nearest, ties towards zero
+	Up                           // away from zero
+	Down                         // towards zero, i.e. truncate
+	Ceiling                      // towards positive infinity
+	Floor                        // towards negative infinity
+)
+
+// round rounds 'd' to 'prec' fractional digits, padding with zeros if it has fewer.
+func (d decimal) round(prec int, mode RoundingMode) decimal {
+	if len(d.frac) <= prec {
+		d.frac += strings.Repeat("0", prec-len(d.frac))
+		return d
+	}
+	kept, rest := d.frac[:prec], d.frac[prec:]
+	nonzero := strings.Trim(rest, "0") != ""
+	tie := rest[0] == '5' && strings.Trim(rest[1:], "0") == ""
+	var up bool
+	switch mode {
+	case HalfEven:
+		digits := d.int + kept
+		odd := (digits[len(digits)-1]-'0')%2 == 1
+		up = rest[0] > '5' || rest[0] == '5' && (!tie || odd)
+	case HalfUp:
+		up = rest[0] >= '5'
+	case HalfDown:
+		up = rest[0] > '5' || rest[0] == '5' && !tie
+	case Up:
+		up = nonzero
+	case Down:
+		up = false
+	case Ceiling:
+		up = nonzero && !d.neg
+	case Floor:
+		up = nonzero && d.neg
+	}
+	d.frac = kept
+	if up {
+		digits := increment(d.int + d.frac)
+		d.int, d.frac = digits[:len(digits)-prec], digits[len(digits)-prec:]
+	}
+	return d.normalize()
+}
+
+// increment adds one to a string of digits, which may grow by one digit.
+func increment(digits string) string {
+	b := []byte(digits)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '9' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b)
+}
@@ -0,0 +1,150 @@
+// Package numfmt formats numbers the way a locale writes them. It generalizes the
+// 'comma' function of exercises 3.10 and 3.11, which always groups digits by three
+// with ',' and uses '.' for the decimal point: here both separators and the sizes of
+// the groups come from a 'Locale', so that India writes 12,34,56,789 (lakhs and
+// crores), Switzerland 123’456’789 and France 123 456 789. Amounts of money are
+// written with the currency symbol where the locale puts it, and numbers can be
+// rounded to a given number of decimals in any of the usual rounding modes.
+//
+// A number can be given as a string, an int, an int64, a float64, a *big.Int or a
+// *big.Float. Strings are validated, and may use scientific notation ("1.5e6");
+// 'Normalize' does that on its own.
+
+package numfmt
+
+import (
+	"strings"
+)
+
+// Locale describes how a language or country writes numbers.
+type Locale struct {
+	Tag     string // e.g. "en-IN"
+	Decimal string // the decimal separator
+	Group   string // the group separator
+	// Grouping gives the sizes of the digit groups, from the decimal separator
+	// leftwards; the last size repeats. {3} groups by thousands, {3, 2} is the Indian
+	// system, and an empty 'Grouping' leaves the digits ungrouped.
+	Grouping []int
+	Minus    string // the minus sign, "-" unless the locale prefers "−" (U+2212)
+	// Currency is where an amount goes relative to the currency symbol: "¤" stands
+	// for the symbol and "#" for the number, e.g. "¤#" for $1.00 or "# ¤" for 1,00 €.
+	Currency string
+}
+
+// The locales below follow the Unicode CLDR data. France and Switzerland group with
+// the narrow no-break space (U+202F) and the right single quotation mark (U+2019)
+// that typography asks for, not a plain space and "'", and the currency symbol is
+// kept on the line of its amount with a no-break space (U+00A0).
+var (
+	English = &Locale{Tag: "en", Decimal: ".", Group: ",", Grouping: []int{3}, Minus: "-", Currency: "¤#"}
+	Indian  = &Locale{Tag: "en-IN", Decimal: ".", Group: ",", Grouping: []int{3, 2}, Minus: "-", Currency: "¤#"}
+	German  = &Locale{Tag: "de", Decimal: ",", Group: ".", Grouping: []int{3}, Minus: "-", Currency: "#\u00a0¤"}
+	Swiss   = &Locale{Tag: "de-CH", Decimal: ".", Group: "’", Grouping: []int{3}, Minus: "-", Currency: "¤\u00a0#"}
+	French  = &Locale{Tag: "fr", Decimal: ",", Group: "\u202f", Grouping: []int{3}, Minus: "-", Currency: "#\u00a0¤"}
+	Greek   = &Locale{Tag: "el", Decimal: ",", Group: ".", Grouping: []int{3}, Minus: "-", Currency: "#\u00a0¤"}
+	Swedish = &Locale{Tag: "sv", Decimal: ",", Group: "\u202f", Grouping: []int{3}, Minus: "\u2212", Currency: "#\u00a0¤"}
+)
+
+// Locales are the known locales, by lower-case tag.
+var Locales = map[string]*Locale{}
+
+func init() {
+	for _, l := range []*Locale{English, Indian, German, Swiss, French, Greek, Swedish} {
+		Locales[strings.ToLower(l.Tag)] = l
+	}
+}
+
+// LocaleFor returns the locale for a tag such as "de", "en-IN" or "fr_FR.UTF-8": the
+// entry for the whole tag if there is one, else the one for its language, else
+// English.
+func LocaleFor(tag string) *Locale {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if l, ok := Locales[tag]; ok {
+		return l
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	if l, ok := Locales[lang]; ok {
+		return l
+	}
+	return English
+}
+
+// Format writes 'x' with all of its digits, e.g. "-1.234.567,89" in German.
+func (l *Locale) Format(x any) (string, error) {
+	d, err := toDecimal(x)
+	if err != nil {
+		return "", err
+	}
+	return l.sign(d) + l.digits(d), nil
+}
+
+// FormatRound writes 'x' rounded to 'prec' decimals, or to a multiple of ten to
+// the power of '-prec' when 'prec' is negative.
+func (l *Locale) FormatRound(x any, prec int, mode RoundingMode) (string, error) {
+	d, err := toDecimal(x)
+	if err != nil {
+		return "", err
+	}
+	d = roundTo(d, prec, mode)
+	return l.sign(d) + l.digits(d), nil
+}
+
+// roundTo rounds to 'prec' decimals, which may be negative: -3 rounds to thousands.
+// For that the point is moved left, the number rounded to an integer, and the point
+// moved back.
+func roundTo(d decimal, prec int, mode RoundingMode) decimal {
+	if prec >= 0 {
+		return d.round(prec, mode)
+	}
+	zeros := strings.Repeat("0", -prec)
+	all := zeros + d.int
+	split := len(all) - len(zeros)
+	r := decimal{d.neg, all[:split], all[split:] + d.frac}.normalize().round(0, mode)
+	if r.int != "0" {
+		r.int += zeros
+	}
+	return r
+}
+
+func (l *Locale) sign(d decimal) string {
+	if d.neg {
+		return l.Minus
+	}
+	return ""
+}
+
+// digits writes the digits of 'd' with the locale's separators, but without a sign.
+func (l *Locale) digits(d decimal) string {
+	s := l.group(d.int)
+	if d.frac != "" {
+		s += l.Decimal + d.frac
+	}
+	return s
+}
+
+// group inserts group separators into a string of digits, starting from the right.
+func (l *Locale) group(digits string) string {
+	if len(l.Grouping) == 0 {
+		return digits
+	}
+	var groups []string
+	for i := 0; len(digits) > 0; i++ {
+		size := l.Grouping[len(l.Grouping)-1]
+		if i < len(l.Grouping) {
+			size = l.Grouping[i]
+		}
+		if size <= 0 || size >= len(digits) {
+			groups = append(groups, digits)
+			break
+		}
+		groups = append(groups, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+	}
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	return strings.Join(groups, l.Group)
+}
@@ -0,0 +1,179 @@
+/* These tests cover grouping in the different locales, every input type, the
+rounding modes and currency placement. Run them with 'go test' in this directory. */
+
+package numfmt
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	big50, _ := new(big.Int).SetString("-12345678901234567890123456789012345678901234567890", 10)
+	bigf, _ := new(big.Float).SetPrec(200).SetString("1234567.125")
+	tests := []struct {
+		l    *Locale
+		x    any
+		want string
+	}{
+		{English, "1234567890", "1,234,567,890"},
+		{English, "-9876543.210", "-9,876,543.210"},
+		{English, "+123", "123"},
+		{English, "-0.0", "0.0"},
+		{English, "0001234", "1,234"},
+		{English, ".5", "0.5"},
+		{Indian, "1234567890", "1,23,45,67,890"},
+		{Indian, int64(100000), "1,00,000"},
+		{Indian, 999, "999"},
+		{Swiss, 1234567.5, "1’234’567.5"},
+		{French, int64(-1234567), "-1\u202f234\u202f567"},
+		{German, 1234.5, "1.234,5"},
+		{Swedish, -12345, "\u221212\u202f345"},
+		{English, math.MaxInt64, "9,223,372,036,854,775,807"},
+		{English, 1 / 3.0, "0.3333333333333333"},
+		{English, 1e21, "1,000,000,000,000,000,000,000"},
+		{English, big50, "-12,345,678,901,234,567,890,123,456,789,012,345,678,901,234,567,890"},
+		{German, bigf, "1.234.567,125"},
+		{&Locale{Decimal: ".", Minus: "-"}, "1234567", "1234567"},
+	}
+	for _, test := range tests {
+		got, err := test.l.Format(test.x)
+		if err != nil || got != test.want {
+			t.Errorf("%s: Format(%v) = %q, %v; expected %q", test.l.Tag, test.x, got, err, test.want)
+		}
+	}
+
+	for _, x := range []any{"", "-", "1.2.3", "12a", "1e", math.NaN(), math.Inf(-1), uint8(1), nil} {
+		if got, err := English.Format(x); err == nil {
+			t.Errorf("Format(%#v) = %q; expected an error", x, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		s, want string
+		err     error
+	}{
+		{"1234", "1234", nil},
+		{"+001.50", "1.50", nil},
+		{"-0", "0", nil},
+		{"5.", "5", nil},
+		{".5", "0.5", nil},
+		{"1e10", "10000000000", nil},
+		{"-1.5E3", "-1500", nil},
+		{"1.5e-3", "0.0015", nil},
+		{"123.456e1", "1234.56", nil},
+		{"12e+2", "1200", nil},
+		{"0e-5", "0.00000", nil},
+		{"1e10000", "1" + strings.Repeat("0", 10000), nil},
+		{"", "", ErrSyntax},
+		{"+", "", ErrSyntax},
+		{"abc", "", ErrSyntax},
+		{"12.3.4", "", ErrSyntax},
+		{".", "", ErrSyntax},
+		{"e5", "", ErrSyntax},
+		{"1e", "", ErrSyntax},
+		{"1e+", "", ErrSyntax},
+		{"1e5.5", "", ErrSyntax},
+		{"1_000", "", ErrSyntax},
+		{" 1", "", ErrSyntax},
+		{"1e10001", "", ErrRange},
+		{"1e99999999999999999999", "", ErrRange},
+	}
+	for _, test := range tests {
+		got, err := Normalize(test.s)
+		if got != test.want || !errors.Is(err, test.err) {
+			t.Errorf("Normalize(%q) = %.20q, %v; expected %.20q, %v", test.s, got, err, test.want, test.err)
+		}
+	}
+}
+
+// FuzzFormat checks that no string makes formatting panic, and that a string that is
+// accepted reads back as the same number once the group separators are removed.
+func FuzzFormat(f *testing.F) {
+	for _, s := range []string{"1234567.89", "-1e10", "+", "", "12.3.4", ".5e-3", "0.000", "9e9999"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		n, err := Normalize(s)
+		for _, l := range []*Locale{English, Indian, Swiss} {
+			got, ferr := l.Format(s)
+			if (ferr == nil) != (err == nil) {
+				t.Fatalf("Format(%q) error = %v, Normalize error = %v", s, ferr, err)
+			}
+			if err == nil && strings.ReplaceAll(got, l.Group, "") != n {
+				t.Fatalf("%s: Format(%q) = %q, expected %q with separators", l.Tag, s, got, n)
+			}
+			if _, err := l.FormatRound(s, 2, HalfEven); (err == nil) != (ferr == nil) {
+				t.Fatalf("FormatRound(%q) error = %v", s, err)
+			}
+		}
+	})
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		x    string
+		prec int
+		want [7]string // HalfEven, HalfUp, HalfDown, Up, Down, Ceiling, Floor
+	}{
+		{"2.5", 0, [7]string{"2", "3", "2", "3", "2", "3", "2"}},
+		{"3.5", 0, [7]string{"4", "4", "3", "4", "3", "4", "3"}},
+		{"-2.5", 0, [7]string{"-2", "-3", "-2", "-3", "-2", "-2", "-3"}},
+		{"2.51", 0, [7]string{"3", "3", "3", "3", "2", "3", "2"}},
+		{"2.675", 2, [7]string{"2.68", "2.68", "2.67", "2.68", "2.67", "2.68", "2.67"}},
+		{"9.999", 2, [7]string{"10.00", "10.00", "10.00", "10.00", "9.99", "10.00", "9.99"}},
+		{"-0.001", 2, [7]string{"0.00", "0.00", "0.00", "-0.01", "0.00", "0.00", "-0.01"}},
+		{"1.5", 3, [7]string{"1.500", "1.500", "1.500", "1.500", "1.500", "1.500", "1.500"}},
+		{"1234567", -3, [7]string{"1,235,000", "1,235,000", "1,235,000", "1,235,000", "1,234,000", "1,235,000", "1,234,000"}},
+		{"2500", -3, [7]string{"2,000", "3,000", "2,000", "3,000", "2,000", "3,000", "2,000"}},
+		{"499", -3, [7]string{"0", "0", "0", "1,000", "0", "1,000", "0"}},
+	}
+	for _, test := range tests {
+		for mode, want := range test.want {
+			got, err := English.FormatRound(test.x, test.prec, RoundingMode(mode))
+			if err != nil || got != want {
+				t.Errorf("FormatRound(%s, %d, mode %d) = %q, %v; expected %q", test.x, test.prec, mode, got, err, want)
+			}
+		}
+	}
+}
+
+func TestCurrency(t *testing.T) {
+	tests := []struct {
+		l    *Locale
+		x    any
+		c    Currency
+		want string
+	}{
+		{English, 1234.5, USD, "$1,234.50"},
+		{English, "-5", USD, "-$5.00"},
+		{German, 1234.5, EUR, "1.234,50\u00a0€"},
+		{French, "1234567.891", EUR, "1\u202f234\u202f567,89\u00a0€"},
+		{Swiss, 99.995, CHF, "CHF\u00a0100.00"},
+		{Indian, int64(12345678), INR, "₹1,23,45,678.00"},
+		{English, 1234.5, JPY, "¥1,234"},
+		{Greek, big.NewInt(3), KWD, "3,000\u00a0KWD"},
+		// A negative number of decimals rounds to tens, hundreds...
+		{English, 1234.5, Currency{"XTS", "T", -2}, "T1,200"},
+	}
+	for _, test := range tests {
+		got, err := test.l.FormatCurrency(test.x, test.c, HalfEven)
+		if err != nil || got != test.want {
+			t.Errorf("%s: FormatCurrency(%v, %s) = %q, %v; expected %q", test.l.Tag, test.x, test.c.Code, got, err, test.want)
+		}
+	}
+}
+
+func TestLocaleFor(t *testing.T) {
+	for tag, want := range map[string]*Locale{"en_IN.UTF-8": Indian, "de-CH": Swiss, "de_AT": German,
+		"fr_FR.UTF-8@euro": French, "xx": English, "": English} {
+		if got := LocaleFor(tag); got != want {
+			t.Errorf("LocaleFor(%q) = %s, expected %s", tag, got.Tag, want.Tag)
+		}
+	}
+}
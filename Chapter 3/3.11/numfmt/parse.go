@@ -0,0 +1,207 @@
+package numfmt
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+/* Parsing goes the other way: "1,234,567.89" in English or "-9 876 543,21" in French
+become exact 'big.Rat' values. The group separators must sit where the locale puts
+them, so "1,23,456" is an error in English but a number in Indian English, and a
+decimal separator of another locale is an error rather than a guess. Since people
+type a plain space or a straight apostrophe for the typographic separators, those
+are accepted in their place.
+
+The package-level 'Parse' does not know the locale and has to guess which of '.' and
+',' is the decimal separator. It is the last of the two if both appear, and the
+group separator if it appears more than once; "1,234" or "1.234" could be either,
+and are rejected with 'ErrAmbiguous'.
+
+'ParseHuman' also takes a multiplier after the number, as in "1.2k", "3.4M" or
+"12 GiB": the SI prefixes from k (10³) to Y (10²⁴) and the binary ones from Ki (2¹⁰)
+to Yi (2⁸⁰), optionally followed by B for bytes. A "k" is written K as often as not,
+and both mean a thousand; "m" would be milli, and is not accepted. */
+
+var ErrAmbiguous = errors.New("ambiguous separator")
+
+// Parse reads a number written in the locale, with an optional sign and exponent.
+// The error is a '*NumError' wrapping 'ErrSyntax' or 'ErrRange'.
+func (l *Locale) Parse(s string) (*big.Rat, error) {
+	d, err := l.parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return d.rat(), nil
+}
+
+// ParseHuman is like 'Parse' but also takes a multiplier suffix; see above.
+func (l *Locale) ParseHuman(s string) (*big.Rat, error) {
+	m := multiplierSuffix.FindStringSubmatchIndex(s)
+	num, suffix := s[:m[2]], s[m[2]:]
+	r, err := l.Parse(num)
+	if err != nil {
+		return nil, &NumError{s, errors.Unwrap(err)}
+	}
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		r.Mul(r, multiplier(suffix))
+	}
+	return r, nil
+}
+
+// Parse reads a number written in an unknown locale; see above.
+func Parse(s string) (*big.Rat, error) {
+	dec, group, err := guessSeparators(s)
+	if err != nil {
+		return nil, err
+	}
+	var first error
+	for _, grouping := range [][]int{{3}, {3, 2}} {
+		l := &Locale{Decimal: dec, Group: group, Grouping: grouping, Minus: "-"}
+		r, err := l.Parse(s)
+		if err == nil {
+			return r, nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return nil, first
+}
+
+// guessSeparators guesses the decimal and group separators of a number. Spaces and
+// apostrophes can only be group separators.
+func guessSeparators(s string) (dec, group string, err error) {
+	dots, commas := strings.Count(s, "."), strings.Count(s, ",")
+	dec = "."
+	switch {
+	case dots > 0 && commas > 0:
+		if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+			dec = ","
+		}
+	case dots > 1:
+		dec = ","
+	case commas == 1:
+		dec = ","
+	}
+	if dots+commas == 1 && ambiguous.MatchString(s) {
+		return "", "", &NumError{s, ErrAmbiguous}
+	}
+	switch {
+	case strings.ContainsAny(strings.TrimSpace(s), spaces):
+		group = " "
+	case strings.ContainsAny(s, "'’"):
+		group = "’"
+	case dec == ".":
+		group = ","
+	default:
+		group = "."
+	}
+	return dec, group, nil
+}
+
+// spaces are the spaces used to group digits: plain, no-break and narrow no-break.
+const spaces = " \u00a0\u202f"
+
+// ambiguous matches a number with one separator that could be a group separator:
+// one to three digits (not just a zero), the separator, and three digits.
+var ambiguous = regexp.MustCompile(`^\s*[-+−]?(?:[1-9]\d{0,2}|0\d{1,2})[.,]\d{3}(?:[eE][-+]?\d+)?\s*$`)
+
+// exponent matches the exponent at the end of a number.
+var exponent = regexp.MustCompile(`[eE][-+]?\d+$`)
+
+// parse reads a number in the locale and turns it into a plain decimal.
+func (l *Locale) parse(s string) (decimal, error) {
+	t := strings.TrimSpace(s)
+	signs := []string{"-", "−", "+", l.Minus}
+	sign := ""
+	for _, m := range signs {
+		if m != "" && strings.HasPrefix(t, m) {
+			if m != "+" {
+				sign = "-"
+			}
+			t = t[len(m):]
+			break
+		}
+	}
+	// One sign only: 'parseDecimal' would take a second one, and read "+-5" as -5.
+	for _, m := range signs {
+		if m != "" && strings.HasPrefix(t, m) {
+			return decimal{}, &NumError{s, ErrSyntax}
+		}
+	}
+	exp := exponent.FindString(t)
+	t = t[:len(t)-len(exp)]
+	intPart, frac, _ := strings.Cut(t, l.Decimal)
+	if l.Group != "" {
+		intPart = l.replaceGroups(intPart)
+		groups := strings.Split(intPart, l.Group)
+		if len(groups) > 1 && !l.validGroups(groups) {
+			return decimal{}, &NumError{s, ErrSyntax}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	d, err := parseDecimal(sign + intPart + "." + frac + exp)
+	if err != nil {
+		return decimal{}, &NumError{s, errors.Unwrap(err)}
+	}
+	return d, nil
+}
+
+// replaceGroups replaces the separators people type for the locale's typographic
+// ones: a plain or no-break space for a narrow one, "'" for "’".
+func (l *Locale) replaceGroups(s string) string {
+	switch l.Group {
+	case " ", "\u00a0", "\u202f":
+		return strings.NewReplacer(" ", l.Group, "\u00a0", l.Group, "\u202f", l.Group).Replace(s)
+	case "’":
+		return strings.ReplaceAll(s, "'", l.Group)
+	}
+	return s
+}
+
+// validGroups reports whether digit groups, split at the group separator, have the
+// sizes of the locale's 'Grouping'. The leftmost group may be shorter.
+func (l *Locale) validGroups(groups []string) bool {
+	if len(l.Grouping) == 0 {
+		return false
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		k := len(groups) - 1 - i
+		size := l.Grouping[len(l.Grouping)-1]
+		if k < len(l.Grouping) {
+			size = l.Grouping[k]
+		}
+		n := len(groups[i])
+		if n != size && !(i == 0 && n > 0 && n < size) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d decimal) rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// multiplierSuffix splits a number from its multiplier; submatch 1 is the suffix,
+// empty if there is none.
+var multiplierSuffix = regexp.MustCompile(`(?s)^.*?(\s*(?:[kKMGTPEZY]i?B?|B)?)$`)
+
+// multiplier returns the value of a multiplier suffix such as "k", "Mi" or "GiB".
+func multiplier(suffix string) *big.Rat {
+	suffix = strings.TrimSuffix(suffix, "B")
+	if suffix == "" {
+		return big.NewRat(1, 1)
+	}
+	exp := int64(strings.IndexByte("kMGTPEZY", suffix[0]) + 1)
+	if suffix[0] == 'K' {
+		exp = 1
+	}
+	if strings.HasSuffix(suffix, "i") {
+		return new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(10*exp)))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(1000), big.NewInt(exp), nil))
+}
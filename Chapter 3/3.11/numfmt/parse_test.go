@@ -0,0 +1,153 @@
+/* These tests cover parsing numbers back: in a given locale, with guessed separators,
+and with multiplier suffixes. Values are compared exactly, as fractions. Run them
+with 'go test' in this directory. */
+
+package numfmt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocaleParse(t *testing.T) {
+	tests := []struct {
+		l    *Locale
+		s    string
+		want string // a fraction for big.Rat.SetString, or "" for an error
+	}{
+		{English, "1,234,567.89", "123456789/100"},
+		{English, "-1,234", "-1234"},
+		{English, "1234567", "1234567"},
+		{English, "+0.5", "1/2"},
+		{English, " 12 ", "12"},
+		{English, "1,234.5e3", "1234500"},
+		{English, "1,23,456", ""},
+		{English, "12,34", ""},
+		{English, "1.234,5", ""},
+		{English, "1,234.5.6", ""},
+		{English, ",123", ""},
+		{English, "+-5", ""},
+		{English, "-+5", ""},
+		{English, "--5", ""},
+		{Indian, "1,23,45,678.5", "24691357/2"},
+		{Indian, "12,345", "12345"},
+		{Indian, "1,234,567", ""},
+		{French, "-9 876 543,21", "-987654321/100"},
+		{French, "-9\u00a0876\u00a0543,21", "-987654321/100"},
+		{French, "-9\u202f876\u202f543,21", "-987654321/100"},
+		{French, "9.876,5", ""},
+		{German, "1.234.567,89", "123456789/100"},
+		{German, "1,5", "3/2"},
+		{Swiss, "1'234'567.25", "4938269/4"},
+		{Swiss, "1’234.5", "2469/2"},
+		{Swedish, "−1\u202f000", "-1000"},
+	}
+	for _, test := range tests {
+		got, err := test.l.Parse(test.s)
+		if test.want == "" {
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("%s: Parse(%q) = %v, %v; expected ErrSyntax", test.l.Tag, test.s, got, err)
+			}
+			continue
+		}
+		if err != nil || got.RatString() != test.want {
+			t.Errorf("%s: Parse(%q) = %v, %v; expected %s", test.l.Tag, test.s, got, err, test.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+		err  error
+	}{
+		{"1,234,567.89", "123456789/100", nil},
+		{"1.234.567,89", "123456789/100", nil},
+		{"1,234.5", "2469/2", nil},
+		{"1.234,5", "2469/2", nil},
+		{"12,5", "25/2", nil},
+		{"1.5", "3/2", nil},
+		{"0.125", "1/8", nil},
+		{"1,23,456", "123456", nil},
+		{"1 234 567,5", "2469135/2", nil},
+		{"1'234", "1234", nil},
+		{"1234", "1234", nil},
+		{"1,234", "", ErrAmbiguous},
+		{"-1.234", "", ErrAmbiguous},
+		{"1,234.5,6", "", ErrSyntax},
+		{"1,2,3", "", ErrSyntax},
+		{"+-5", "", ErrSyntax},
+		{"-+5", "", ErrSyntax},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.s)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("Parse(%q) = %v, %v; expected %v", test.s, got, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got.RatString() != test.want {
+			t.Errorf("Parse(%q) = %v, %v; expected %s", test.s, got, err, test.want)
+		}
+	}
+}
+
+func TestParseHuman(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"1.2k", "1200"},
+		{"1.2K", "1200"},
+		{"3.4M", "3400000"},
+		{"12 GiB", "12884901888"},
+		{"1.5Ki", "1536"},
+		{"2 TB", "2000000000000"},
+		{"7B", "7"},
+		{"1,024", "1024"},
+		{"1e3k", "1000000"},
+		{"2E", "2000000000000000000"},
+		{"1 YiB", "1208925819614629174706176"},
+	}
+	for _, test := range tests {
+		got, err := English.ParseHuman(test.s)
+		if err != nil || got.RatString() != test.want {
+			t.Errorf("ParseHuman(%q) = %v, %v; expected %s", test.s, got, err, test.want)
+		}
+	}
+	for _, s := range []string{"3.4m", "k", "12 GiBs", "1.2kk", "", "+-5k", "-+5"} {
+		if got, err := English.ParseHuman(s); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParseHuman(%q) = %v, %v; expected ErrSyntax", s, got, err)
+		}
+	}
+	if got, err := German.ParseHuman("1,5 MiB"); err != nil || got.RatString() != "1572864" {
+		t.Errorf("German ParseHuman(1,5 MiB) = %v, %v", got, err)
+	}
+}
+
+// FuzzParse checks that parsing never panics, and that whatever 'Format' writes,
+// 'Parse' reads back in the same locale.
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"1,234,567.89", "-9 876 543,21", "1.2k", "12 GiB", "1,234", "", "e", "-", "\n0"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		Parse(s)
+		for _, l := range []*Locale{English, Indian, French} {
+			l.ParseHuman(s)
+			d, err := l.parse(s)
+			if err != nil {
+				continue
+			}
+			formatted, err := l.Format(d.String())
+			if err != nil {
+				t.Fatalf("%s: Format(%s): %v", l.Tag, d, err)
+			}
+			back, err := l.Parse(formatted)
+			if r := d.rat(); err != nil || back.Cmp(r) != 0 {
+				t.Fatalf("%s: Parse(%q) = %v, formatted as %q, read back as %v, %v", l.Tag, s, r, formatted, back, err)
+			}
+		}
+	})
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
 	"sort"
 	"strings"
+
+	"GoBookSolutions/3.12/anagram"
 )
 
 func isAnagram(str1, str2 string) bool {
@@ -32,13 +37,105 @@ func isAnagram(str1, str2 string) bool {
 	return string(runes1) == string(runes2)
 }
 
+/* With '-dict', the words given as arguments are looked up in a word list instead, through the
+'anagram' package, which sorts the letters of every word of the list once and groups the words
+that share them. '-words' allows phrases of up to that many words, and '-top' prints the largest
+groups of anagrams in the list:
+
+	go run . -dict /usr/share/dict/words -words 2 -min 3 listen dormitory
+	go run . -dict /usr/share/dict/words -top 5
+
+Without '-dict', the program compares two strings given as arguments with the package's
+options, or else the two strings of the exercise. By default case is ignored, as are spaces and
+punctuation, and text is normalized to NFC; '-norm', '-case', '-marks' and '-strict' change that:
+
+	go run . -marks "Résumé" "resume"
+	go run . -norm nfkc "ﬁle" "life" */
+
+var (
+	dict      = flag.String("dict", "", "word list, one word per line")
+	maxWords  = flag.Int("words", 1, "find phrases of up to this many words")
+	minLen    = flag.Int("min", 2, "shortest word to use in phrases")
+	limit     = flag.Int("limit", 20, "most phrases to print per word")
+	top       = flag.Int("top", 0, "print the largest groups of anagrams")
+	form      = flag.String("norm", "nfc", "Unicode normalization: none, nfc or nfkc")
+	matchCase = flag.Bool("case", false, "tell upper and lower case apart")
+	marks     = flag.Bool("marks", false, "ignore diacritics, so that é is e")
+	strict    = flag.Bool("strict", false, "compare spaces and punctuation too")
+)
+
+// options turns the flags into the options of the 'anagram' package.
+func options() (anagram.Options, error) {
+	opt := anagram.Options{
+		FoldCase:          !*matchCase,
+		StripDiacritics:   *marks,
+		IgnoreSpace:       !*strict,
+		IgnorePunctuation: !*strict,
+	}
+	switch strings.ToLower(*form) {
+	case "none", "":
+		opt.Form = anagram.NoForm
+	case "nfc":
+		opt.Form = anagram.NFC
+	case "nfkc":
+		opt.Form = anagram.NFKC
+	default:
+		return opt, fmt.Errorf("unknown normalization form %q (want none, nfc or nfkc)", *form)
+	}
+	return opt, nil
+}
+
 func main() {
-	str1 := "Listen"
-	str2 := "Silent"
+	flag.Parse()
+	opt, err := options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *dict == "" {
+		if flag.NArg() == 2 {
+			if opt.IsAnagram(flag.Arg(0), flag.Arg(1)) {
+				fmt.Printf("%q and %q are anagrams.\n", flag.Arg(0), flag.Arg(1))
+			} else {
+				fmt.Printf("%q and %q are not anagrams.\n", flag.Arg(0), flag.Arg(1))
+			}
+			return
+		}
+		str1 := "Listen"
+		str2 := "Silent"
+
+		if isAnagram(str1, str2) {
+			println("The strings are anagrams.")
+		} else {
+			println("The strings are not anagrams.")
+		}
+		return
+	}
 
-	if isAnagram(str1, str2) {
-		println("The strings are anagrams.")
-	} else {
-		println("The strings are not anagrams.")
+	f, err := os.Open(*dict)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	ix, err := anagram.LoadWith(f, opt)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *top > 0 {
+		for _, g := range ix.Largest(*top) {
+			fmt.Printf("%d: %s\n", len(g), strings.Join(g, " "))
+		}
+	}
+	for _, word := range flag.Args() {
+		if *maxWords <= 1 {
+			fmt.Printf("%s: %s\n", word, strings.Join(ix.Anagrams(word), " "))
+			continue
+		}
+		phrases := ix.Phrases(word, anagram.PhraseOptions{MaxWords: *maxWords, MinLength: *minLen, Limit: *limit})
+		for _, p := range phrases {
+			fmt.Printf("%s: %s\n", word, strings.Join(p, " "))
+		}
 	}
 }
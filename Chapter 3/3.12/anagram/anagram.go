@@ -0,0 +1,136 @@
+// Package anagram finds anagrams in a word list. Exercise 3.12 compares two strings
+// by sorting their letters; here every word of the list is sorted once, when the
+// 'Index' is built, and words with the same sorted letters (the same signature) are
+// kept together. A query then only sorts its own letters and looks them up.
+//
+// Besides single-word anagrams ("listen" → "silent", "enlist", "tinsel"), the index
+// finds phrases: combinations of words that together use the letters of a phrase,
+// such as "dirty room" for "dormitory".
+
+package anagram
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Signature returns the canonical form of a word or phrase under the 'Default'
+// options: its letters and digits, case folded and sorted. Spaces and punctuation
+// are left out, so that "Dormitory" and "dirty room" have the same signature.
+func Signature(s string) string {
+	return Default.Signature(s)
+}
+
+// Index groups the words of a list by signature.
+type Index struct {
+	opt    Options
+	groups map[string][]string // words by signature, in the order of the list
+	sigs   []string            // the signatures, longest first, for 'Phrases'
+	parts  map[string][]string // the sorted clusters of each signature
+	words  int
+}
+
+// NewIndex builds an index of 'words' with the 'Default' options.
+func NewIndex(words []string) *Index {
+	return NewIndexWith(words, Default)
+}
+
+// NewIndexWith builds an index of 'words' that compares them under 'opt'. Words that
+// occur more than once are kept once, and words left empty by the options are
+// skipped.
+func NewIndexWith(words []string, opt Options) *Index {
+	ix := &Index{opt: opt, groups: make(map[string][]string), parts: make(map[string][]string)}
+	seen := make(map[string]bool)
+	for _, w := range words {
+		clusters := opt.sorted(w)
+		sig := join(clusters)
+		if sig == "" || seen[w] {
+			continue
+		}
+		seen[w] = true
+		if ix.groups[sig] == nil {
+			ix.sigs = append(ix.sigs, sig)
+			ix.parts[sig] = clusters
+		}
+		ix.groups[sig] = append(ix.groups[sig], w)
+		ix.words++
+	}
+	sort.SliceStable(ix.sigs, func(i, j int) bool {
+		return len(ix.parts[ix.sigs[i]]) > len(ix.parts[ix.sigs[j]])
+	})
+	return ix
+}
+
+// Load reads a word list with one word per line, such as /usr/share/dict/words.
+// Surrounding spaces are trimmed and empty lines skipped.
+func Load(r io.Reader) (*Index, error) {
+	return LoadWith(r, Default)
+}
+
+// LoadWith is 'Load' with the options of 'NewIndexWith'.
+func LoadWith(r io.Reader, opt Options) (*Index, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if w := strings.TrimSpace(scanner.Text()); w != "" {
+			words = append(words, w)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewIndexWith(words, opt), nil
+}
+
+// LoadFile is 'Load' for a file.
+func LoadFile(name string) (*Index, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Len returns the number of words in the index.
+func (ix *Index) Len() int { return ix.words }
+
+// Anagrams returns the words of the list that are anagrams of 'word', other than
+// 'word' itself (in any case).
+func (ix *Index) Anagrams(word string) []string {
+	var out []string
+	for _, w := range ix.groups[ix.opt.Signature(word)] {
+		if !strings.EqualFold(w, word) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Largest returns the 'n' largest groups of anagrams, the largest first; groups of
+// the same size are ordered by their first word. Words without anagrams do not form
+// a group. 'n' of zero or less returns none.
+func (ix *Index) Largest(n int) [][]string {
+	if n <= 0 {
+		return nil
+	}
+	var groups [][]string
+	for _, g := range ix.groups {
+		if len(g) > 1 {
+			groups = append(groups, g)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i]) != len(groups[j]) {
+			return len(groups[i]) > len(groups[j])
+		}
+		return groups[i][0] < groups[j][0]
+	})
+	if len(groups) > n {
+		groups = groups[:n]
+	}
+	return groups
+}
@@ -0,0 +1,117 @@
+/* These tests build an index of a small word list and check single-word anagrams,
+phrase anagrams with their limits, and the largest groups. Run them with 'go test'
+in this directory. */
+
+package anagram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const words = `listen
+silent
+enlist
+tinsel
+inlets
+Listen
+google
+dormitory
+dirty
+room
+dim
+or
+tory
+a
+evil
+vile
+live
+veil
+Levi
+dusty
+study
+evil`
+
+func index(t *testing.T) *Index {
+	ix, err := Load(strings.NewReader(words))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ix
+}
+
+func TestSignature(t *testing.T) {
+	for _, s := range []string{"Dormitory", "dirty room", "DIRTY-ROOM!"} {
+		if got := Signature(s); got != "dimoorrty" {
+			t.Errorf("Signature(%q) = %q, expected dimoorrty", s, got)
+		}
+	}
+}
+
+func TestAnagrams(t *testing.T) {
+	ix := index(t)
+	if ix.Len() != 21 {
+		t.Errorf("Len() = %d, expected 21 (the duplicate 'evil' is dropped)", ix.Len())
+	}
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"listen", []string{"silent", "enlist", "tinsel", "inlets"}},
+		{"Silent", []string{"listen", "enlist", "tinsel", "inlets", "Listen"}},
+		{"study", []string{"dusty"}},
+		{"google", nil},
+		{"nothing", nil},
+	}
+	for _, test := range tests {
+		if got := ix.Anagrams(test.word); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Anagrams(%q) = %q, expected %q", test.word, got, test.want)
+		}
+	}
+}
+
+func TestPhrases(t *testing.T) {
+	ix := index(t)
+	got := ix.Phrases("Dormitory", PhraseOptions{MaxWords: 2})
+	want := [][]string{{"dirty", "room"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Phrases(Dormitory, 2 words) = %q, expected %q", got, want)
+	}
+	got = ix.Phrases("dirty room", PhraseOptions{MaxWords: 3})
+	want = [][]string{{"dormitory"}, {"dirty", "room"}, {"tory", "dim", "or"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Phrases(dirty room, 3 words) = %q, expected %q", got, want)
+	}
+	got = ix.Phrases("dirty room", PhraseOptions{MaxWords: 3, MinLength: 3})
+	want = [][]string{{"dormitory"}, {"dirty", "room"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Phrases(dirty room, min 3 letters) = %q, expected %q", got, want)
+	}
+	if got := ix.Phrases("vile evil", PhraseOptions{Limit: 4}); len(got) != 4 {
+		t.Errorf("Phrases(vile evil, limit 4) returned %d phrases", len(got))
+	}
+	if got := ix.Phrases("xyz", PhraseOptions{}); got != nil {
+		t.Errorf("Phrases(xyz) = %q, expected none", got)
+	}
+}
+
+func TestLargest(t *testing.T) {
+	ix := index(t)
+	got := ix.Largest(2)
+	want := [][]string{
+		{"listen", "silent", "enlist", "tinsel", "inlets", "Listen"},
+		{"evil", "vile", "live", "veil", "Levi"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Largest(2) = %q, expected %q", got, want)
+	}
+	if got := ix.Largest(10); len(got) != 3 {
+		t.Errorf("Largest(10) returned %d groups, expected 3", len(got))
+	}
+	for _, n := range []int{0, -1} {
+		if got := ix.Largest(n); got != nil {
+			t.Errorf("Largest(%d) = %q, expected none", n, got)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package anagram
+
+import "strings"
+
+/* A phrase anagram is a set of words whose signatures, put together, give the
+signature of the phrase. The search only looks at signatures, never at words: first
+it keeps the signatures whose letters all occur in the phrase, then it takes them
+away from the phrase one by one, in the order of the list of candidates (so "dirty
+room" and "room dirty" are found once), until no letter is left. Each combination
+of signatures then stands for every choice of one word per signature.
+
+The number of phrases grows very fast with the length of the phrase and the number
+of words allowed, and short words such as "a" or "I" fit almost anywhere, which is
+what 'PhraseOptions' is for. */
+
+// PhraseOptions limits the search for phrases.
+type PhraseOptions struct {
+	MaxWords  int // the most words in a phrase; 0 means 3
+	MinLength int // the fewest letters (clusters) in a word; 0 allows any word
+	Limit     int // the most phrases to return; 0 returns all
+}
+
+// Phrases returns the phrases of words from the list that are anagrams of 's', each
+// as a list of words, longest first. 's' itself is not returned.
+func (ix *Index) Phrases(s string, opt PhraseOptions) [][]string {
+	if opt.MaxWords <= 0 {
+		opt.MaxWords = 3
+	}
+	target := ix.opt.sorted(s)
+	var cands []string
+	for _, sig := range ix.sigs {
+		if len(ix.parts[sig]) < opt.MinLength {
+			continue
+		}
+		if _, ok := subtract(target, ix.parts[sig]); ok {
+			cands = append(cands, sig)
+		}
+	}
+	p := &phraseSearch{ix: ix, cands: cands, opt: opt, query: strings.Join(strings.Fields(s), " ")}
+	p.search(target, 0, nil)
+	return p.out
+}
+
+type phraseSearch struct {
+	ix    *Index
+	cands []string
+	opt   PhraseOptions
+	query string
+	out   [][]string
+}
+
+// search finds the combinations of candidates from 'start' on that use up the letters
+// in 'rest'. It reports false once the limit is reached.
+func (p *phraseSearch) search(rest []string, start int, chosen []string) bool {
+	if len(rest) == 0 {
+		return p.expand(chosen, nil)
+	}
+	if len(chosen) == p.opt.MaxWords {
+		return true
+	}
+	for i := start; i < len(p.cands); i++ {
+		if r, ok := subtract(rest, p.ix.parts[p.cands[i]]); ok {
+			if !p.search(r, i, append(chosen, p.cands[i])) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// expand adds every phrase with one word for each of the signatures in 'sigs'.
+func (p *phraseSearch) expand(sigs []string, words []string) bool {
+	if len(sigs) == 0 {
+		if len(words) == 1 && strings.EqualFold(words[0], p.query) {
+			return true
+		}
+		p.out = append(p.out, append([]string(nil), words...))
+		return p.opt.Limit == 0 || len(p.out) < p.opt.Limit
+	}
+	for _, w := range p.ix.groups[sigs[0]] {
+		if !p.expand(sigs[1:], append(words, w)) {
+			return false
+		}
+	}
+	return true
+}
+
+// subtract removes the clusters of signature 'b' from signature 'a', both given as
+// sorted clusters. It reports false if 'b' has a cluster that 'a' does not have, or
+// has it more often. Both are sorted, so this is a merge.
+func subtract(a, b []string) ([]string, bool) {
+	var rest []string
+	i := 0
+	for _, c := range b {
+		for i < len(a) && a[i] < c {
+			rest = append(rest, a[i])
+			i++
+		}
+		if i == len(a) || a[i] != c {
+			return nil, false
+		}
+		i++
+	}
+	return append(rest, a[i:]...), true
+}
@@ -0,0 +1,264 @@
+package anagram
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+/* What counts as "the same letters" is less obvious than it looks once text is not
+plain ASCII. "é" can be written as one code point (U+00E9) or as "e" followed by a
+combining acute accent (U+0301); sorting runes would tear the accent off its letter,
+and "éa" and "eá", with the accent on different letters, would come out the same.
+So a string is compared as a list of grapheme clusters, the characters
+a reader sees, each made of a base rune and whatever attaches to it:
+
+  - combining marks (accents, vowel signs and the like);
+  - variation selectors and emoji skin-tone modifiers;
+  - anything joined to it with a zero width joiner, as in the emoji sequences for
+    families or professions;
+  - a second regional indicator, which makes a flag such as "🇫🇷", and the tag
+    characters of the flags of England, Scotland and Wales;
+  - "\n" after "\r".
+
+This covers the text one meets in word lists, but it is not the whole of Unicode's
+segmentation rules (UAX #29): Hangul syllables written as separate jamo and a few
+scripts' prepended marks are split where the standard would not split them.
+
+Before that the string can be normalized (NFC composes "e" + U+0301 into "é"; NFKC
+also turns compatibility characters such as the ligature "ﬁ" into "fi"), case folded,
+and stripped of its diacritics, and spaces and punctuation can be left out. */
+
+// Form is the Unicode normalization applied before comparing.
+type Form int
+
+const (
+	NoForm Form = iota // compare the code points as they are
+	NFC                // canonical composition: "e" + U+0301 is "é"
+	NFKC               // compatibility composition: also "ﬁ" is "fi", "①" is "1"
+)
+
+// Options says which differences between two strings do not matter.
+type Options struct {
+	Form              Form
+	FoldCase          bool // "A" is "a", and "ß" is "ss"
+	StripDiacritics   bool // "é" is "e"
+	IgnoreSpace       bool
+	IgnorePunctuation bool
+}
+
+// Default is what 'Signature', 'NewIndex' and 'Load' use: NFC, case folded, without
+// spaces and punctuation. Diacritics, digits and symbols are kept.
+var Default = Options{Form: NFC, FoldCase: true, IgnoreSpace: true, IgnorePunctuation: true}
+
+// Signature returns the canonical form of 's' under the options: its grapheme clusters,
+// sorted and put together again.
+func (o Options) Signature(s string) string {
+	return join(o.sorted(s))
+}
+
+/* Putting the clusters back together as they are loses where one ends and the next
+begins: without normalization, "e" + U+0301 is one cluster and U+0301 then "e" are
+two, which sort to the same bytes. 'join' keeps the common case, clusters of one
+valid rune, as it is, so that the signature of "Dormitory" is still "dimoorrty", and
+writes any other cluster as 'escape', its length in bytes, a colon and its bytes.
+Reading a signature rune by rune then gives back its clusters, so two signatures
+are equal only if their clusters are. */
+
+// escape starts a cluster of more than one rune in a signature.
+const escape = '\x00'
+
+// join puts the sorted clusters 'clusters' together into a signature.
+func join(clusters []string) string {
+	var b strings.Builder
+	for _, c := range clusters {
+		r, n := utf8.DecodeRuneInString(c)
+		if n == len(c) && r != utf8.RuneError && r != escape {
+			b.WriteString(c)
+			continue
+		}
+		b.WriteRune(escape)
+		b.WriteString(strconv.Itoa(len(c)))
+		b.WriteByte(':')
+		b.WriteString(c)
+	}
+	return b.String()
+}
+
+// sorted returns the clusters of 's' that are not ignored, sorted.
+func (o Options) sorted(s string) []string {
+	clusters := o.clusters(s)
+	sort.Strings(clusters)
+	return clusters
+}
+
+// clusters normalizes 's' and returns its grapheme clusters that are not ignored, in
+// order.
+func (o Options) clusters(s string) []string {
+	var out []string
+	for _, c := range Graphemes(o.normalize(s)) {
+		if !o.ignored(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// normalize applies the case folding and normalization of the options. Folding comes
+// first, since it can give text that is no longer normalized.
+func (o Options) normalize(s string) string {
+	if o.FoldCase {
+		s = cases.Fold().String(s)
+	}
+	if o.StripDiacritics {
+		// Decompose, so that accents become marks of their own, drop the marks and
+		// compose what is left.
+		decompose := norm.NFD
+		if o.Form == NFKC {
+			decompose = norm.NFKD
+		}
+		s = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, decompose.String(s))
+		return norm.NFC.String(s)
+	}
+	switch o.Form {
+	case NFC:
+		s = norm.NFC.String(s)
+	case NFKC:
+		s = norm.NFKC.String(s)
+	}
+	return s
+}
+
+// ignored reports whether cluster 'c' is left out; it is classified by its base rune.
+func (o Options) ignored(c string) bool {
+	r, _ := utf8.DecodeRuneInString(c)
+	return o.IgnoreSpace && unicode.IsSpace(r) || o.IgnorePunctuation && unicode.IsPunct(r)
+}
+
+// Graphemes splits 's' into grapheme clusters, as described above.
+func Graphemes(s string) []string {
+	var out []string
+	start := 0
+	var prev rune = -1
+	joined := false // the previous rune was a zero width joiner
+	regional := 0   // regional indicators in the current cluster
+	for i, r := range s {
+		extend := i > 0 && (joined || extends(r) || prev == '\r' && r == '\n' ||
+			isRegional(r) && regional%2 == 1)
+		if !extend && i > 0 {
+			out = append(out, s[start:i])
+			start = i
+			regional = 0
+		}
+		if isRegional(r) {
+			regional++
+		}
+		joined = r == zwj
+		prev = r
+	}
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
+
+const zwj = '\u200d' // zero width joiner
+
+// extends reports whether 'r' attaches to the rune before it.
+func extends(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r == zwj,
+		'\ufe00' <= r && r <= '\ufe0f', // variation selectors
+		0x1f3fb <= r && r <= 0x1f3ff,   // emoji modifiers (skin tones)
+		0xe0020 <= r && r <= 0xe007f,   // tags
+		0xe0100 <= r && r <= 0xe01ef:   // variation selectors supplement
+		return true
+	}
+	return false
+}
+
+func isRegional(r rune) bool { return 0x1f1e6 <= r && r <= 0x1f1ff }
+
+/* 'Signature' sorts, which takes O(n log n) for n clusters. Comparing two strings
+does not need the order, only how often each cluster occurs, and counting takes
+O(n): count up for the clusters of one string, down for the other, and check that
+every count is back to zero. For ASCII, where every byte is a cluster (except "\r\n")
+and normalization changes nothing, the counts fit in an array and no string is even
+cut into pieces. */
+
+// IsAnagram reports whether 'a' and 'b' have the same clusters under the options, as
+// 'o.Signature(a) == o.Signature(b)' does, in linear time.
+func (o Options) IsAnagram(a, b string) bool {
+	if isASCII(a) && isASCII(b) {
+		var counts [257]int
+		o.countASCII(&counts, a, 1)
+		o.countASCII(&counts, b, -1)
+		return counts == [257]int{}
+	}
+	counts := make(map[string]int)
+	for _, c := range o.clusters(a) {
+		counts[c]++
+	}
+	for _, c := range o.clusters(b) {
+		if counts[c] == 0 {
+			return false
+		}
+		counts[c]--
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// crlf is the slot of "\r\n" in the counts of 'countASCII'.
+const crlf = 256
+
+// countASCII adds 'delta' to the count of each cluster of 's', which is ASCII.
+func (o Options) countASCII(counts *[257]int, s string, delta int) {
+	for i := 0; i < len(s); i++ {
+		c := int(s[i])
+		if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+			c = crlf
+			i++
+		}
+		switch {
+		case c == crlf || asciiSpace(s[i]):
+			if o.IgnoreSpace {
+				continue
+			}
+		case o.IgnorePunctuation && unicode.IsPunct(rune(c)):
+			continue
+		case o.FoldCase && 'A' <= c && c <= 'Z':
+			c += 'a' - 'A'
+		}
+		counts[c] += delta
+	}
+}
+
+func asciiSpace(b byte) bool {
+	return b == ' ' || '\t' <= b && b <= '\r'
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+/* These tests check the comparison options on text that is not plain ASCII, the
+grapheme clusters, and that counting agrees with sorting. The benchmarks compare
+the two, and the rune sorting of 'isAnagram' in 3.12.go that both replace, on words
+and sentences of a few lengths:
+
+	go test -bench . */
+
+package anagram
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		opt  Options
+		a, b string
+		want bool
+	}{
+		{Default, "Dormitory", "dirty room", true},
+		{Default, "Eleven plus two", "Twelve plus one!", true},
+		{Options{}, "Dormitory", "dirty room", false},
+		{Options{FoldCase: true}, "Listen", "Silent", true},
+		{Options{FoldCase: true}, "dormitory", "dirty room", false},
+		{Options{IgnoreSpace: true}, "dormitory", "dirty room", true},
+		{Options{}, "Listen", "Silent", false},
+		{Default, "4 wheels", "whee1s 4", false},
+
+		// "é" precomposed, and as "e" with a combining accent.
+		{Default, "\u00e9t\u00e9", "te\u0301e\u0301", true},
+		{Options{}, "\u00e9t\u00e9", "te\u0301e\u0301", false},
+		// The accent belongs to a letter; it is not a letter of its own.
+		{Default, "e\u0301a", "ea\u0301", false},
+		{Default, "e\u0301a", "a\u00e9", true},
+		{Default, "Straße", "SSTRAES", true},
+		{Default, "résumé", "resume", false},
+		{Options{StripDiacritics: true}, "résumé", "resume", true},
+		{Options{StripDiacritics: true}, "résumé", "müsere", true},
+		{Options{Form: NFC}, "ﬁle", "life", false},
+		{Default, "ﬁle", "life", true}, // case folding also splits the ligature
+		{Options{Form: NFKC}, "ﬁle", "life", true},
+		{Options{Form: NFKC}, "①②", "21", true},
+		{Options{Form: NFKC, StripDiacritics: true}, "ﬁancé", "ecnaif", true},
+
+		// Emoji sequences and flags move as one piece.
+		{Default, "a\U0001f44d\U0001f3fdb", "b\U0001f44d\U0001f3fda", true},
+		{Default, "a\U0001f44d\U0001f3fdb", "b\U0001f3fd\U0001f44da", false},
+		{Default, "\U0001f1eb\U0001f1f7\U0001f1e9\U0001f1ea", "\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7", true},
+		{Default, "\U0001f1eb\U0001f1f7\U0001f1e9\U0001f1ea", "\U0001f1eb\U0001f1ea\U0001f1e9\U0001f1f7", false},
+		{Default, "x\U0001f469\u200d\U0001f4bby", "y\U0001f469\u200d\U0001f4bbx", true},
+		{Default, "x\U0001f469\u200d\U0001f4bby", "y\U0001f4bb\u200d\U0001f469x", false},
+
+		// Clusters that give the same bytes once sorted and put together.
+		{Options{}, "e\u0301", "\u0301e", false},
+		{Options{}, "e\u0301a", "ae\u0301", true},
+		{Options{Form: NFKC, StripDiacritics: true, IgnorePunctuation: true}, "\u0301!\u00e9\U000e0020", "\U000e0020\u00e9", false},
+		{Options{}, "a\u200d\xff", "\xffa\u200d", false},
+		{Options{}, "\x00", "\x00", true},
+	}
+	for _, test := range tests {
+		if got := test.opt.Signature(test.a) == test.opt.Signature(test.b); got != test.want {
+			t.Errorf("%+v: Signature(%q) == Signature(%q) is %t", test.opt, test.a, test.b, got)
+		}
+		if got := test.opt.IsAnagram(test.a, test.b); got != test.want {
+			t.Errorf("%+v: IsAnagram(%q, %q) = %t", test.opt, test.a, test.b, got)
+		}
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"abc", []string{"a", "b", "c"}},
+		{"e\u0301a", []string{"e\u0301", "a"}},
+		{"\u0301a", []string{"\u0301", "a"}},
+		{"a\r\nb\n\r", []string{"a", "\r\n", "b", "\n", "\r"}},
+		{"\U0001f1eb\U0001f1f7\U0001f1e9", []string{"\U0001f1eb\U0001f1f7", "\U0001f1e9"}},
+		{"\U0001f469\u200d\U0001f4bb!", []string{"\U0001f469\u200d\U0001f4bb", "!"}},
+		{"\u2764\ufe0f\U0001f44b\U0001f3ff", []string{"\u2764\ufe0f", "\U0001f44b\U0001f3ff"}},
+		{"", nil},
+	}
+	for _, test := range tests {
+		if got := Graphemes(test.s); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Graphemes(%q) = %q, expected %q", test.s, got, test.want)
+		}
+	}
+}
+
+func TestIndexOptions(t *testing.T) {
+	words := []string{"résumé", "resume", "müsere", "Musée"}
+	ix := NewIndex(words)
+	if got := ix.Anagrams("resume"); got != nil {
+		t.Errorf("Anagrams(resume) = %q, expected none with diacritics", got)
+	}
+	opt := Default
+	opt.StripDiacritics = true
+	ix = NewIndexWith(words, opt)
+	want := []string{"résumé", "müsere"}
+	if got := ix.Anagrams("resume"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Anagrams(resume) = %q, expected %q", got, want)
+	}
+	got := ix.Phrases("Sue rem", PhraseOptions{MaxWords: 2})
+	if len(got) != 3 || got[0][0] != "résumé" {
+		t.Errorf("Phrases(Sue rem) = %q, expected the three words with its letters", got)
+	}
+}
+
+// FuzzIsAnagram checks that counting agrees with sorting.
+func FuzzIsAnagram(f *testing.F) {
+	for _, s := range [][2]string{{"listen", "silent"}, {"a\r\n", "\na\r"}, {"e\u0301a", "a\u00e9"}, {"Straße", "strasse"},
+		{"e\u0301", "\u0301e"}, {"\u0301!\u00e9\U000e0020", "\U000e0020\u00e9"}} {
+		f.Add(s[0], s[1])
+	}
+	opts := []Options{{}, Default, {Form: NFKC, StripDiacritics: true, IgnorePunctuation: true}}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		for _, opt := range opts {
+			want := opt.Signature(a) == opt.Signature(b)
+			if got := opt.IsAnagram(a, b); got != want {
+				t.Fatalf("%+v: IsAnagram(%q, %q) = %t, sorting says %t", opt, a, b, got, want)
+			}
+		}
+	})
+}
+
+// benchmarkInputs returns a pair of anagrams of about 'n' letters each: the second is
+// the first reversed.
+func benchmarkInputs(base string, n int) (string, string) {
+	r := []rune(strings.Repeat(base, n/len(base)+1))[:n]
+	a := string(r)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return a, string(r)
+}
+
+var benchmarkSink bool
+
+func benchmark(b *testing.B, base string, n int, compare func(a, b string) bool) {
+	x, y := benchmarkInputs(base, n)
+	b.SetBytes(int64(len(x) + len(y)))
+	for i := 0; i < b.N; i++ {
+		benchmarkSink = compare(x, y)
+	}
+}
+
+func sortCompare(a, b string) bool { return Default.Signature(a) == Default.Signature(b) }
+
+// runeSortCompare is 'isAnagram' of 3.12.go, which cannot be imported from package
+// main: it lowercases both strings and compares their sorted runes.
+func runeSortCompare(a, b string) bool {
+	r1, r2 := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	sort.Slice(r1, func(i, j int) bool { return r1[i] < r1[j] })
+	sort.Slice(r2, func(i, j int) bool { return r2[i] < r2[j] })
+	return string(r1) == string(r2)
+}
+
+const (
+	asciiText   = "The quick brown fox jumps over the lazy dog. "
+	unicodeText = "Größere Bären überqueren die Straße. "
+)
+
+func BenchmarkRuneSortASCII10(b *testing.B)     { benchmark(b, asciiText, 10, runeSortCompare) }
+func BenchmarkRuneSortASCII1000(b *testing.B)   { benchmark(b, asciiText, 1000, runeSortCompare) }
+func BenchmarkRuneSortUnicode10(b *testing.B)   { benchmark(b, unicodeText, 10, runeSortCompare) }
+func BenchmarkRuneSortUnicode1000(b *testing.B) { benchmark(b, unicodeText, 1000, runeSortCompare) }
+
+func BenchmarkSortASCII10(b *testing.B)     { benchmark(b, asciiText, 10, sortCompare) }
+func BenchmarkCountASCII10(b *testing.B)    { benchmark(b, asciiText, 10, Default.IsAnagram) }
+func BenchmarkSortASCII1000(b *testing.B)   { benchmark(b, asciiText, 1000, sortCompare) }
+func BenchmarkCountASCII1000(b *testing.B)  { benchmark(b, asciiText, 1000, Default.IsAnagram) }
+func BenchmarkSortUnicode10(b *testing.B)   { benchmark(b, unicodeText, 10, sortCompare) }
+func BenchmarkCountUnicode10(b *testing.B)  { benchmark(b, unicodeText, 10, Default.IsAnagram) }
+func BenchmarkSortUnicode1000(b *testing.B) { benchmark(b, unicodeText, 1000, sortCompare) }
+func BenchmarkCountUnicode1000(b *testing.B) {
+	benchmark(b, unicodeText, 1000, Default.IsAnagram)
+}
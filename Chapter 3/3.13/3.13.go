@@ -1,24 +1,23 @@
-/* In the code below, the iota starts at '0' and increments by '1' for each constant declaration.
-The '<<' operator is used for bit-shifting to calculate the value of each constant in bytes
-(1 KB = 1024 bytes, 1 MB = 1024 KB, etc.). However, any further use of iota would lead to an
-overflow because iota can only represent up to 2^63-1 in signed integers, and we need to go
-beyond that to represent ZB and YB. For that reason, we assigned the two constants as strings
-giving them the exact value of a zettabyte and a yottabyte. */
+/* The constants live in the 'bytesize' package, so that other exercises (such as the
+unit converter of 2.2) can import them. This program prints them, and how
+'bytesize.Format' writes each of them in binary and in decimal units. */
 
 package main
 
-const (
-	_         = iota
-	KB uint64 = 1 << (iota * 10)
-	MB
-	GB
-	TB
-	PB
-	EB
-	ZB = "1180591620717411303424"
-	YB = "1208925819614629174706176"
+import (
+	"fmt"
+	"math/big"
+
+	"GoBookSolutions/3.13/bytesize"
 )
 
 func main() {
-	println(KB, MB, GB, TB, PB, EB, ZB, YB)
+	sizes := []*big.Int{}
+	for _, n := range []uint64{bytesize.KB, bytesize.MB, bytesize.GB, bytesize.TB, bytesize.PB, bytesize.EB} {
+		sizes = append(sizes, new(big.Int).SetUint64(n))
+	}
+	sizes = append(sizes, bytesize.BigZB, bytesize.BigYB)
+	for _, n := range sizes {
+		fmt.Printf("%-26s %-8s %s\n", n, bytesize.FormatBig(n, bytesize.IEC), bytesize.FormatBig(n, bytesize.SI))
+	}
 }
@@ -0,0 +1,34 @@
+// Package bytesize holds the binary multiples of the byte, from KB (1024 bytes) to
+// YB, so that other exercises can use them instead of their own copies. It also
+// writes byte counts for people, as in "1.5 GiB" or "1.6 GB", reads such sizes back
+// exactly, and provides a 'flag.Value' for size flags.
+
+package bytesize
+
+import "math/big"
+
+/* In the code below, the iota starts at '0' and increments by '1' for each constant declaration.
+The '<<' operator is used for bit-shifting to calculate the value of each constant in bytes
+(1 KB = 1024 bytes, 1 MB = 1024 KB, etc.). However, any further use of iota would lead to an
+overflow, because a uint64 can only represent up to 2^64-1 and we need to go beyond that to
+represent ZB and YB. For that reason, ZB and YB are untyped constants: Go computes constants
+exactly, whatever their size, and they only overflow when they are given a type too small
+for them. 'float64(ZB)' is exact, since it is a power of two, but 'uint64(ZB)' does not
+compile; 'BigZB' and 'BigYB' hold them as 'big.Int' values for exact arithmetic. */
+
+const (
+	_         = iota
+	KB uint64 = 1 << (iota * 10)
+	MB
+	GB
+	TB
+	PB
+	EB
+	ZB = 1 << 70
+	YB = 1 << 80
+)
+
+var (
+	BigZB = new(big.Int).Lsh(big.NewInt(1), 70)
+	BigYB = new(big.Int).Lsh(big.NewInt(1), 80)
+)
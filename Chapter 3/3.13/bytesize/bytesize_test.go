@@ -0,0 +1,105 @@
+/* These tests cover formatting in both modes, exact parsing, and the flag value. Run
+them with 'go test' in this directory. */
+
+package bytesize
+
+import (
+	"flag"
+	"io"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		n       uint64
+		iec, si string
+	}{
+		{0, "0 B", "0 B"},
+		{512, "512 B", "512 B"},
+		{1023, "1023 B", "1 kB"},
+		{KB, "1 KiB", "1 kB"},
+		{1536, "1.5 KiB", "1.5 kB"},
+		{3 * GB / 2, "1.5 GiB", "1.6 GB"},
+		{MB - 20, "1 MiB", "1 MB"},
+		{1000 * 1000, "976.6 KiB", "1 MB"},
+		{EB, "1 EiB", "1.2 EB"},
+		{math.MaxUint64, "16 EiB", "18.4 EB"},
+	}
+	for _, test := range tests {
+		if got := Format(test.n, IEC); got != test.iec {
+			t.Errorf("Format(%d, IEC) = %q, expected %q", test.n, got, test.iec)
+		}
+		if got := Format(test.n, SI); got != test.si {
+			t.Errorf("Format(%d, SI) = %q, expected %q", test.n, got, test.si)
+		}
+	}
+	huge := new(big.Int).Mul(BigYB, big.NewInt(2048))
+	if got := FormatBig(huge, IEC); got != "2048 YiB" {
+		t.Errorf("FormatBig(2048 YiB) = %q", got)
+	}
+	if got := FormatBig(new(big.Int).Neg(BigZB), IEC); got != "-1 ZiB" {
+		t.Errorf("FormatBig(-ZB) = %q", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"512", "512"},
+		{"512 B", "512"},
+		{"1.5 GiB", "1610612736"},
+		{"1.6GB", "1600000000"},
+		{"4 kib", "4096"},
+		{"4K", "4000"},
+		{"2 ZiB", "2361183241434822606848"},
+		{"1 YiB", BigYB.String()},
+		{".5 KiB", "512"},
+		{"3 MiB", "3145728"},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.s)
+		if err != nil || got.String() != test.want {
+			t.Errorf("Parse(%q) = %v, %v; expected %s", test.s, got, err, test.want)
+		}
+	}
+	for _, s := range []string{"", "GiB", "1.5 GiBs", "-1 KiB", "0.3 KiB", "1e3", "1 XB", "1.2.3 MB"} {
+		if got, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) = %v; expected an error", s, got)
+		}
+	}
+	if _, err := ParseUint64("16 EiB"); err == nil {
+		t.Errorf("ParseUint64(16 EiB) succeeded")
+	}
+
+	// Every formatted IEC size reads back as the size, rounded to the shown decimal.
+	for _, n := range []uint64{1, 1536, 3 * GB / 2, 5 * TB} {
+		s := Format(n, IEC)
+		if got, err := ParseUint64(s); err != nil || got != n {
+			t.Errorf("ParseUint64(Format(%d)) = ParseUint64(%q) = %d, %v", n, s, got, err)
+		}
+	}
+}
+
+func TestFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cache, limit := Size(64*MB), Size(0)
+	fs.Var(&cache, "cache", "cache size")
+	fs.Var(&limit, "limit", "size limit")
+	if err := fs.Parse([]string{"-limit", "1.5GB"}); err != nil {
+		t.Fatal(err)
+	}
+	if cache != Size(64*MB) || limit != 1500000000 {
+		t.Errorf("flags = %v, %v; expected 64 MiB and 1500000000 bytes", cache, limit)
+	}
+	if cache.String() != "64 MiB" {
+		t.Errorf("String() = %q, expected 64 MiB", cache.String())
+	}
+	if err := fs.Parse([]string{"-cache", "lots"}); err == nil || cache != Size(64*MB) {
+		t.Errorf("-cache lots: error %v, value %v", err, cache)
+	}
+}
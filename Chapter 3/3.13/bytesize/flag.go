@@ -0,0 +1,25 @@
+package bytesize
+
+import "flag"
+
+// Size is a number of bytes. It prints itself in IEC units and implements
+// 'flag.Value', so that a command can take '-cache 512MiB' or '-limit 1.5GB'.
+type Size uint64
+
+func (s Size) String() string { return Format(uint64(s), IEC) }
+
+// Set parses a size as 'Parse' does. The size is left alone on error.
+func (s *Size) Set(v string) error {
+	n, err := ParseUint64(v)
+	if err == nil {
+		*s = Size(n)
+	}
+	return err
+}
+
+// SizeFlag defines a Size flag with the given name, default value and usage on the
+// default flag set, and returns the address of the variable that holds it.
+func SizeFlag(name string, value Size, usage string) *Size {
+	flag.CommandLine.Var(&value, name, usage)
+	return &value
+}
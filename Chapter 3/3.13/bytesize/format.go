@@ -0,0 +1,109 @@
+package bytesize
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+/* Sizes are written in the largest unit that leaves at least 1 before the point, with
+one decimal that is left out when it is zero: "512 B", "1 KiB", "1.5 GiB". There are
+two sets of units. IEC units are powers of 1024 (KiB, MiB, ...), which is what the
+constants above are; SI units are powers of 1000 (kB, MB, ...), which is what disks
+are sold in. A 1.5 GiB file is 1.6 GB. The constants keep the names the book gives
+them, KB to YB, although by the standards those names are the SI ones.
+
+Parsing is exact: "1.5 GiB" is 1610612736 bytes, computed with 'big.Rat', and a size
+that is not a whole number of bytes, such as "0.3 KiB", is an error. Units are read
+without regard to case, since people type "gb" as often as "GB"; a unit with an 'i'
+is binary, one without decimal, and a bare number is a count of bytes. */
+
+// Mode selects binary or decimal units.
+type Mode int
+
+const (
+	IEC Mode = iota // powers of 1024: KiB, MiB, GiB, ...
+	SI              // powers of 1000: kB, MB, GB, ...
+)
+
+var symbols = [...][]string{
+	IEC: {"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"},
+	SI:  {"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"},
+}
+
+func (m Mode) base() *big.Int {
+	if m == SI {
+		return big.NewInt(1000)
+	}
+	return big.NewInt(1024)
+}
+
+// Format writes a number of bytes in the best-fitting unit, e.g. "1.5 GiB".
+func Format(n uint64, m Mode) string {
+	return FormatBig(new(big.Int).SetUint64(n), m)
+}
+
+// FormatBig is 'Format' for sizes of any magnitude. Sizes from 1024 YiB (or 1000 YB)
+// up are written in YiB (YB).
+func FormatBig(n *big.Int, m Mode) string {
+	syms := symbols[m]
+	base := m.base()
+	abs := new(big.Int).Abs(n)
+	unit := big.NewInt(1)
+	i := 0
+	for next := new(big.Int).Mul(unit, base); i < len(syms)-1 && abs.Cmp(next) >= 0; next.Mul(next, base) {
+		unit.Set(next)
+		i++
+	}
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+	}
+	if i == 0 {
+		return sign + abs.String() + " B"
+	}
+	s := new(big.Rat).SetFrac(abs, unit).FloatString(1)
+	if r, _ := new(big.Rat).SetString(s); i < len(syms)-1 && r.Cmp(new(big.Rat).SetInt(base)) >= 0 {
+		// Rounding took it up to the next unit: 1023.96 KiB is written 1 MiB.
+		s = new(big.Rat).SetFrac(abs, unit.Mul(unit, base)).FloatString(1)
+		i++
+	}
+	return sign + strings.TrimSuffix(s, ".0") + " " + syms[i]
+}
+
+var size = regexp.MustCompile(`(?i)^\s*(\d+\.?\d*|\.\d+)\s*(?:([kmgtpezy])(i?)b?|b)?\s*$`)
+
+// Parse reads a size such as "1.5 GiB", "1.6GB", "512" or "4 kib" into a number of
+// bytes.
+func Parse(s string) (*big.Int, error) {
+	m := size.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("bytesize: invalid size %q", s)
+	}
+	r, _ := new(big.Rat).SetString(m[1])
+	if m[2] != "" {
+		mode := SI
+		if m[3] != "" {
+			mode = IEC
+		}
+		exp := int64(strings.Index("kmgtpezy", strings.ToLower(m[2])) + 1)
+		r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(mode.base(), big.NewInt(exp), nil)))
+	}
+	if !r.IsInt() {
+		return nil, fmt.Errorf("bytesize: %q is not a whole number of bytes", s)
+	}
+	return r.Num(), nil
+}
+
+// ParseUint64 is 'Parse' for sizes that fit in a uint64.
+func ParseUint64(s string) (uint64, error) {
+	n, err := Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("bytesize: %q is too large", s)
+	}
+	return n.Uint64(), nil
+}
@@ -0,0 +1,3 @@
+module GoBookSolutions/3.13
+
+go 1.20
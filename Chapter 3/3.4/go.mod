@@ -0,0 +1,3 @@
+module GoBookSolutions/3.4
+
+go 1.20
@@ -0,0 +1,114 @@
+// Package surface renders the 3-D surface plot of section 3.2 as SVG. It started as
+// the body of the 3.4 web server and was moved here so that other servers can mount
+// it as an 'http.Handler'.
+
+package surface
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// 'width' and 'height' are the default max canvas size. That means that we can't change the 2 values
+// any higher than 600x320. To do that, we would have to implement some HTTP/Javascript code that would
+// set the canvas size to whatever the native resolution of the user's monitor would be.
+const (
+	width, height = 600, 320
+	cells         = 100
+	xyrange       = 30.0
+	xyscale       = width / 2 / xyrange
+	zscale        = height * 0.4
+	angle         = math.Pi / 6
+)
+
+var sin30, cos30 = math.Sin(angle), math.Cos(angle)
+
+// DefaultColor is the fill color used when the request does not provide one.
+const DefaultColor = "white"
+
+// Handler serves the surface as 'image/svg+xml'. It reads the 'width', 'height' and
+// 'color' query parameters.
+type Handler struct{}
+
+func (Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "image/svg+xml") // You can check if the Content-Type header is correct
+	// with a tool like curl in a terminal. Type the following:
+	// curl -I http://localhost:8000
+
+	// We used the 'r.FormValue' function to be able to extract the three query parameters
+	// (width, height, color) from the HTTP request.
+	widthInt, err := strconv.Atoi(r.FormValue("width"))
+	if err != nil || widthInt <= 0 {
+		widthInt = width
+	}
+	heightInt, err := strconv.Atoi(r.FormValue("height"))
+	if err != nil || heightInt <= 0 {
+		heightInt = height
+	}
+	color := r.FormValue("color")
+	if !validColor(color) {
+		color = DefaultColor // Here we set the default color of the surface, if no valid color is provided.
+	}
+
+	SVG(w, widthInt, heightInt, color)
+}
+
+// SVG writes the surface with the given canvas size and fill color to 'out'.
+func SVG(out io.Writer, w, h int, color string) {
+	/* Here we use a format string '%s' to include the value of the color variable in place of '%s'.
+	The value of color will be the fill color of the SVG elements, and it can be specified
+	by the user as a query parameter in the HTTP request. */
+	fmt.Fprintf(out, "<svg xmlns='http://www.w3.org/2000/svg' "+
+		"style='stroke: grey; fill: %s; stroke-width: 0.7' "+
+		"width='%d' height='%d'>", color, w, h)
+
+	for i := 0; i < cells; i++ {
+		for j := 0; j < cells; j++ {
+			ax, ay := corner(i+1, j)
+			bx, by := corner(i, j)
+			cx, cy := corner(i, j+1)
+			dx, dy := corner(i+1, j+1)
+			fmt.Fprintf(out, "<polygon points='%g,%g %g,%g %g,%g %g,%g'/>\n",
+				ax, ay, bx, by, cx, cy, dx, dy)
+		}
+	}
+	fmt.Fprintln(out, "</svg>")
+}
+
+// validColor accepts color names and '#rgb'/'#rrggbb' values. The color ends up
+// inside the SVG 'style' attribute, so anything else (quotes, semicolons, markup)
+// is rejected rather than echoed into the document.
+func validColor(s string) bool {
+	if s == "" || len(s) > 32 {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '#' && i == 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func corner(i, j int) (float64, float64) {
+
+	x := xyrange * (float64(i)/cells - 0.5)
+	y := xyrange * (float64(j)/cells - 0.5)
+
+	z := f(x, y)
+
+	sx := width/2 + (x-y)*cos30*xyscale
+	sy := height/2 + (x+y)*sin30*xyscale - z*zscale
+	return sx, sy
+}
+
+func f(x, y float64) float64 {
+	r := math.Hypot(x, y)
+	return math.Sin(r) / r
+}
@@ -0,0 +1,3 @@
+module GoBookSolutions/3.9
+
+go 1.20
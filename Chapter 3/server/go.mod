@@ -0,0 +1,15 @@
+module GoBookSolutions/server
+
+go 1.20
+
+require (
+	GoBookSolutions/1.12 v0.0.0
+	GoBookSolutions/3.4 v0.0.0
+	GoBookSolutions/3.9 v0.0.0
+)
+
+replace (
+	GoBookSolutions/1.12 => "../../Chapter 1/1.12"
+	GoBookSolutions/3.4 => ../3.4
+	GoBookSolutions/3.9 => ../3.9
+)
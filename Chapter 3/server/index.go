@@ -0,0 +1,52 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+
+	"GoBookSolutions/1.12/lissajous"
+	"GoBookSolutions/3.4/surface"
+	"GoBookSolutions/3.9/fractal"
+)
+
+// index serves the landing page. Every other path that is not mounted gets a 404,
+// since the '/' pattern of 'http.ServeMux' matches everything.
+func index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	indexPage.Execute(w, struct {
+		DefaultCycles, MaxCycles int
+		DefaultColor             string
+		FractalTypes             []string
+	}{lissajous.DefaultCycles, lissajous.MaxCycles, surface.DefaultColor, fractal.Types})
+}
+
+var indexPage = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>GoBookSolutions</title>
+<style>body{font-family:sans-serif;max-width:40em;margin:2em auto}fieldset{margin-bottom:1em}label{display:inline-block;min-width:6em}</style>
+</head><body>
+<h1>GoBookSolutions</h1>
+
+<form action="/lissajous"><fieldset><legend>Lissajous (1.12)</legend>
+<label for="cycles">cycles</label><input id="cycles" name="cycles" type="number" min="1" max="{{.MaxCycles}}" value="{{.DefaultCycles}}">
+<button>Draw</button></fieldset></form>
+
+<form action="/surface"><fieldset><legend>Surface (3.4)</legend>
+<label for="width">width</label><input id="width" name="width" type="number" min="1" value="600"><br>
+<label for="height">height</label><input id="height" name="height" type="number" min="1" value="320"><br>
+<label for="color">color</label><input id="color" name="color" value="{{.DefaultColor}}">
+<button>Draw</button></fieldset></form>
+
+<form action="/fractal"><fieldset><legend>Fractal (3.9)</legend>
+<label for="type">type</label><select id="type" name="type">{{range .FractalTypes}}<option>{{.}}</option>{{end}}</select><br>
+<label for="x">x</label><input id="x" name="x" type="number" step="any" value="0"><br>
+<label for="y">y</label><input id="y" name="y" type="number" step="any" value="0"><br>
+<label for="zoom">zoom</label><input id="zoom" name="zoom" type="number" step="any" value="1">
+<button>Draw</button></fieldset></form>
+
+<p><a href="/debug/echo">/debug/echo</a> &middot; <a href="/healthz">/healthz</a></p>
+</body></html>
+`))
@@ -0,0 +1,102 @@
+// The 1.12, 3.4 and 3.9 web servers each used to be a binary of their own, all of them
+// listening on ':8000'. This server mounts the three of them side by side:
+//
+//	/lissajous   animated Lissajous figure (1.12), 'cycles'
+//	/surface     SVG surface plot (3.4), 'width', 'height', 'color'
+//	/fractal     PNG fractals (3.9), 'type', 'x', 'y', 'zoom'
+//	/debug/echo  request echo (1.12)
+//	/healthz     liveness check
+//	/            index page with a form for each of the tools
+//
+// Run it with 'go run . -addr :8080'. The listen address can also come from the ADDR
+// environment variable. On SIGINT or SIGTERM the server stops accepting connections,
+// lets in-flight requests finish and then exits.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"GoBookSolutions/1.12/debugecho"
+	"GoBookSolutions/1.12/lissajous"
+	"GoBookSolutions/3.4/surface"
+	"GoBookSolutions/3.9/fractal"
+)
+
+func main() {
+	addr := flag.String("addr", envOr("ADDR", ":8000"), "listen address")
+	grace := flag.Duration("shutdown-timeout", 10*time.Second, "how long to wait for in-flight requests on shutdown")
+	flag.Parse()
+
+	var stopping atomic.Bool
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           logRequests(log.Default(), newRouter(&stopping)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s", *addr)
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	// From here on '/healthz' reports 503 so that a load balancer stops sending us
+	// traffic while the in-flight requests drain.
+	stopping.Store(true)
+	log.Printf("shutting down, waiting up to %s", *grace)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *grace)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+	if err := <-errc; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Print(err)
+	}
+}
+
+// newRouter mounts every tool on its own path. The 'stopping' flag is read by the
+// health check.
+func newRouter(stopping *atomic.Bool) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/lissajous", lissajous.Handler{})
+	mux.Handle("/surface", surface.Handler{})
+	mux.Handle("/fractal", fractal.Handler{})
+	mux.Handle("/debug/echo", debugecho.New())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if stopping.Load() {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/", index)
+	return mux
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
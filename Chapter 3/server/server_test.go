@@ -0,0 +1,61 @@
+// This test checks that every tool is mounted where the index page says it is and
+// that the health check follows the shutdown flag. Run it with 'go test'.
+
+package main
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	var stopping atomic.Bool
+	var logs bytes.Buffer
+	srv := httptest.NewServer(logRequests(log.New(&logs, "", 0), newRouter(&stopping)))
+	defer srv.Close()
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{"/", 200, "text/html"},
+		{"/lissajous?cycles=1", 200, "image/gif"},
+		{"/surface?color=red", 200, "image/svg+xml"},
+		{"/fractal?type=newton&zoom=4", 200, "image/png"},
+		{"/debug/echo", 200, "text/plain"},
+		{"/healthz", 200, "text/plain"},
+		{"/nope", 404, "text/plain"},
+	}
+	for _, test := range tests {
+		resp, err := http.Get(srv.URL + test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("GET %s: status %d, expected %d", test.path, resp.StatusCode, test.status)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, test.contentType) {
+			t.Errorf("GET %s: Content-Type %q, expected %s", test.path, ct, test.contentType)
+		}
+	}
+	if !strings.Contains(logs.String(), "GET /nope 404") {
+		t.Errorf("request log is missing the 404 line:\n%s", logs.String())
+	}
+
+	stopping.Store(true)
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("GET /healthz while stopping: status %d, expected 503", resp.StatusCode)
+	}
+}